@@ -0,0 +1,214 @@
+package sinoname
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// BatchResult holds the result of one value of a batch.
+type BatchResult struct {
+	// Index is the index of the value in the batch.
+	Index int
+	// Input is the value passed through the pipeline.
+	Input string
+	// Result holds the values generated for Input and how the pipeline ended.
+	Result
+	// Err is the error which aborted the pipeline for Input.
+	Err error
+}
+
+// GenerateBatch passes each of the inputs through the pipeline like GenerateDetailed.
+//
+// No value is returned twice across the whole batch: the values validated by the source
+// for an input are treated as taken for all the other inputs, the values which arent
+// returned are given back once the input is generated. The inputs are generated
+// concurrently, up to MaxBatchConcurrency at a time, and share the MaxConcurrency limit.
+//
+// The results are ordered like the inputs, an error aborting the pipeline for one input
+// is reported in its result without affecting the other inputs. If the context is
+// cancelled the results of the unfinished inputs are aborted and the context error is
+// returned.
+func (g *Generator) GenerateBatch(ctx context.Context, inputs []string) ([]BatchResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]BatchResult, len(inputs))
+	for i, in := range inputs {
+		results[i] = BatchResult{
+			Index: i,
+			Input: in,
+			Result: Result{
+				Status: StatusAborted,
+			},
+			Err: context.Canceled,
+		}
+	}
+
+	inC := make(chan string)
+	go func() {
+		defer close(inC)
+
+		for _, in := range inputs {
+			select {
+			case <-ctx.Done():
+				return
+			case inC <- in:
+			}
+		}
+	}()
+
+	for res := range g.GenerateStream(ctx, inC) {
+		results[res.Index] = res
+	}
+
+	return results, ctx.Err()
+}
+
+// GenerateStream passes each value read from inputs through the pipeline like
+// GenerateBatch, sending the results as soon as they are ready. The Index of each result
+// is the position of the value in the stream.
+//
+// The returned channel is closed once inputs is closed and all its values are generated,
+// or once the context is cancelled.
+func (g *Generator) GenerateStream(ctx context.Context, inputs <-chan string) <-chan BatchResult {
+	n := g.cfg.MaxBatchConcurrency
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	limit := newSemaphore(n)
+	claims := newClaimSet()
+	// one semaphore for the whole batch.
+	ctx = contextWithSemaphore(ctx, newSemaphore(g.cfg.MaxConcurrency))
+
+	outC := make(chan BatchResult)
+	go func() {
+		var wg sync.WaitGroup
+		defer close(outC)
+		defer wg.Wait()
+
+		for i := 0; ; i++ {
+			var in string
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-inputs:
+				if !ok {
+					return
+				}
+				in = v
+			}
+
+			if !limit.acquire(ctx) {
+				return
+			}
+			wg.Add(1)
+			go func(i int, in string) {
+				defer wg.Done()
+				defer limit.release()
+
+				res, err := g.generateDetailed(ctx, in, generateState{
+					claims: claims,
+					owner:  i,
+				})
+				select {
+				case <-ctx.Done():
+				case outC <- BatchResult{Index: i, Input: in, Result: res, Err: err}:
+				}
+			}(i, in)
+		}
+	}()
+
+	return outC
+}
+
+// claimSet holds the values claimed by the generate calls of a batch, each call is the
+// owner of its claims.
+//
+// A value validated by the source is reserved by its owner so that no other call can use
+// it, once the value is read by the owner the claim is final. When the owner finishes
+// its reservations are released.
+type claimSet struct {
+	mu       sync.Mutex
+	claims   map[string]claim
+	reserved map[int][]string
+}
+
+type claim struct {
+	owner int
+	final bool
+}
+
+func newClaimSet() *claimSet {
+	return &claimSet{
+		claims:   make(map[string]claim),
+		reserved: make(map[int][]string),
+	}
+}
+
+// available reports wether v can be claimed by owner.
+func (s *claimSet) available(v string, owner int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.claims[v]
+	return !ok || c.owner == owner
+}
+
+// reserve reserves v for owner, it returns false if v is claimed by another owner.
+func (s *claimSet) reserve(v string, owner int) bool {
+	return s.claim(v, owner, false)
+}
+
+// finalize claims v for good, it returns false if v is claimed by another owner.
+func (s *claimSet) finalize(v string, owner int) bool {
+	return s.claim(v, owner, true)
+}
+
+func (s *claimSet) claim(v string, owner int, final bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.claims[v]
+	if ok && c.owner != owner {
+		return false
+	}
+	if !ok {
+		s.reserved[owner] = append(s.reserved[owner], v)
+	}
+	s.claims[v] = claim{owner, c.final || final}
+	return true
+}
+
+// release releases all the reservations of owner which werent finalized.
+func (s *claimSet) release(owner int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.reserved[owner] {
+		if !s.claims[v].final {
+			delete(s.claims, v)
+		}
+	}
+	delete(s.reserved, owner)
+}
+
+// claimedSource rejects the values claimed by other owners without calling the source
+// and reserves the values validated by the source for its owner.
+type claimedSource struct {
+	claims *claimSet
+	owner  int
+	src    Source
+}
+
+func (s *claimedSource) Valid(ctx context.Context, v string) (bool, error) {
+	if !s.claims.available(v, s.owner) {
+		return false, nil
+	}
+
+	ok, err := s.src.Valid(ctx, v)
+	if err != nil || !ok {
+		return ok, err
+	}
+	return s.claims.reserve(v, s.owner), nil
+}
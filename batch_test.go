@@ -0,0 +1,105 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestGenerateBatch(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	newGen := func(src Source) *Generator {
+		return New(&Config{
+			MaxBytes:            testConfig.MaxBytes,
+			MaxVals:             2,
+			MaxChanges:          10,
+			MaxConcurrency:      4,
+			MaxBatchConcurrency: 8,
+			PreventDefault:      true,
+			Source:              src,
+		}).WithTransformers(
+			IncrementalSuffix(100, "_"),
+			IncrementalPrefix(100, "_"),
+		)
+	}
+
+	t.Run("Unique", func(t *testing.T) {
+		inputs := make([]string, 40)
+		for i := range inputs {
+			inputs[i] = "john"
+			if i%2 == 0 {
+				inputs[i] = "jane"
+			}
+		}
+
+		results, err := newGen(noopSource{true}).GenerateBatch(context.Background(), inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seen := make(map[string]bool)
+		for i, res := range results {
+			if res.Index != i || res.Input != inputs[i] || res.Err != nil {
+				t.Fatalf("unexpected result %v for input %v", res, i)
+			}
+			if len(res.Values) != 2 {
+				t.Fatalf("expected 2 values got %v", res.Values)
+			}
+
+			for _, v := range res.Values {
+				if seen[v] {
+					t.Fatalf("value %v returned twice", v)
+				}
+				seen[v] = true
+			}
+		}
+	})
+
+	t.Run("Error_Per_Input", func(t *testing.T) {
+		errSrc := errors.New("source error")
+		results, err := newGen(errSource{errSrc}).GenerateBatch(context.Background(), []string{"foo", "bar"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, res := range results {
+			if !errors.Is(res.Err, errSrc) || res.Status != StatusAborted {
+				t.Fatalf("expected %v got %v", errSrc, res.Err)
+			}
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		inC := make(chan string)
+		go func() {
+			defer close(inC)
+			for _, in := range []string{"a", "b", "c"} {
+				inC <- in
+			}
+		}()
+
+		var n int
+		for res := range newGen(noopSource{true}).GenerateStream(context.Background(), inC) {
+			if res.Err != nil || len(res.Values) != 2 {
+				t.Fatalf("unexpected result %v", res)
+			}
+			n++
+		}
+		if n != 3 {
+			t.Fatalf("expected 3 results got %v", n)
+		}
+	})
+
+	t.Run("Context_Cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := newGen(noopSource{true}).GenerateBatch(ctx, []string{"foo"})
+		if err != context.Canceled || results[0].Status != StatusAborted {
+			t.Fatalf("expected %v got %v", context.Canceled, err)
+		}
+	})
+}
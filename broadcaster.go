@@ -2,8 +2,10 @@ package sinoname
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"sync"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 )
@@ -45,9 +47,28 @@ type packetBroadcaster struct {
 	// pWg is a waitgroup used to monitor the values which are and will be processed.
 	pWg *sync.WaitGroup
 	// used to run go-routines: processValues and runTransformer.
-	runner *errgroup.Group
+	runner runner
+	// used to run the listen go-routine.
+	spawn func(func())
+
+	// layerSem limits the number of transformers running at the same time in this layer.
+	layerSem semaphore
+	// globalSem limits the number of transformers running at the same time in the
+	// whole pipeline, it is shared by all the layers of one Generate call.
+	//
+	// Both semaphores are only held for the duration of Transform (including the time a
+	// timed out transformer keeps running), never while writing to the wait queues, so a
+	// full downstream layer cant starve the upstream layers of slots.
+	globalSem semaphore
 
 	transformers []Transformer
+	// names of the transformers, reported to the observer and in errors.
+	names []string
+	// layer is the index of the top level layer in the pipeline and path the unique path
+	// of the layer, see TransformEvent.LayerPath .
+	layer int
+	path  []int
+	obs   Observer
 	// the wait queue for waiters.
 	receive []chan *waiter
 
@@ -72,6 +93,17 @@ func newPacketBroadcatser(ctx context.Context, cfg *Config, src <-chan MessagePa
 		recievers[i] = make(chan *waiter)
 	}
 
+	var layerSem semaphore
+	if cfg != nil {
+		layerSem = newSemaphore(cfg.MaxLayerConcurrency)
+	}
+
+	names := make([]string, len(t))
+	for i, tr := range t {
+		names[i] = transformerName(tr)
+	}
+
+	runner, spawn := runnerFor(ctx, g)
 	return &packetBroadcaster{
 		src: src,
 		cfg: cfg,
@@ -79,9 +111,17 @@ func newPacketBroadcatser(ctx context.Context, cfg *Config, src <-chan MessagePa
 
 		lWg:    sync.WaitGroup{},
 		pWg:    &sync.WaitGroup{},
-		runner: g,
+		runner: runner,
+		spawn:  spawn,
+
+		layerSem:  layerSem,
+		globalSem: semaphoreFromContext(ctx),
 
 		transformers: t,
+		names:        names,
+		layer:        layerFromContext(ctx),
+		path:         layerPathFromContext(ctx),
+		obs:          observerFor(ctx, cfg),
 		receive:      recievers,
 
 		handleValue: handleValue,
@@ -99,7 +139,7 @@ func (b *packetBroadcaster) StartListen() {
 		)
 	}
 
-	go b.listen()
+	b.spawn(b.listen)
 }
 
 func (b *packetBroadcaster) listen() {
@@ -116,18 +156,23 @@ func (b *packetBroadcaster) listen() {
 			}
 
 			if v.Changes > b.cfg.MaxChanges {
-				b.handleSkip(b.ctx, nil, -1, v)
+				b.skipLayer(v)
 				continue
 			}
 			if v.Skip > 0 {
 				v.Skip--
-				b.handleSkip(b.ctx, nil, -1, v)
+				b.skipLayer(v)
 				continue
 			}
 
 			idV := b.valuesCount
-			b.lWg.Add(len(b.transformers))
 			for i, t := range b.transformers {
+				if !b.acquire() {
+					b.exit(true)
+					return
+				}
+
+				b.lWg.Add(1)
 				b.runner.Go(b.runTransformer(t, v, i, idV))
 			}
 			b.valuesCount++
@@ -135,9 +180,66 @@ func (b *packetBroadcaster) listen() {
 	}
 }
 
+// skipLayer lets the packet skip the whole layer.
+func (b *packetBroadcaster) skipLayer(v MessagePacket) {
+	err := observe(b.ctx, b.cfg, func() {
+		b.obs.OnSkip(b.ctx, SkipEvent{
+			Layer:     b.layer,
+			LayerPath: b.path,
+			Index:     -1,
+			Packet:    v,
+		})
+	})
+	b.fail(err)
+	b.handleSkip(b.ctx, nil, -1, v)
+}
+
+// fail reports the observer error err to the errgroup from the listener go-routine, which
+// doesent run in the errgroup. ErrSkip is dropped, the message is skipped already.
+//
+// fail must be called before the wait queues are closed, while the value processors
+// keep the errgroup running.
+func (b *packetBroadcaster) fail(err error) {
+	if err == nil || err == ErrSkip {
+		return
+	}
+	b.runner.Go(func() error { return err })
+}
+
+// acquire takes a slot from the layer semaphore and then from the global semaphore.
+//
+// The layer slot is taken first so that a layer waiting on its own limit doesent hold
+// a global slot which other layers could use.
+func (b *packetBroadcaster) acquire() bool {
+	if !b.layerSem.acquire(b.ctx) {
+		return false
+	}
+	if !b.globalSem.acquire(b.ctx) {
+		b.layerSem.release()
+		return false
+	}
+
+	return true
+}
+
+func (b *packetBroadcaster) release() {
+	b.globalSem.release()
+	b.layerSem.release()
+}
+
 func (b *packetBroadcaster) exit(cancel bool) {
 	// wait for all values to be writted to their specific recieve channels.
 	b.lWg.Wait()
+	// the exit is reported before closing the wait queues so that a panicking observer
+	// can still fail the pipeline, see fail.
+	b.fail(observe(b.ctx, b.cfg, func() {
+		b.obs.OnLayerExit(b.ctx, LayerEvent{
+			Layer:     b.layer,
+			LayerPath: b.path,
+			Values:    b.valuesCount,
+			Cancelled: cancel,
+		})
+	}))
 	for _, ch := range b.receive {
 		close(ch)
 	}
@@ -153,10 +255,50 @@ func (b *packetBroadcaster) exit(cancel bool) {
 //
 // If the transformer returns an error, simillarly routine exits and the waitgroup is decremented.
 // When the routine exits it also returns the transformer error cancelling the context.
+//
+// If the transformer or the observer panics the panic is handled according to the
+// PanicPolicy config field.
 func (b *packetBroadcaster) runTransformer(t Transformer, v MessagePacket, idT, idV int) func() error {
 	return func() error {
 		defer b.lWg.Done()
-		out, err := t.Transform(b.ctx, v)
+		ctx := contextWithTransformer(b.ctx, idT, b.names[idT], t)
+		e := TransformEvent{
+			Layer:       b.layer,
+			LayerPath:   b.path,
+			Index:       idT,
+			Transformer: b.names[idT],
+			In:          v,
+		}
+		out, err := v, observe(ctx, b.cfg, func() { b.obs.OnTransformStart(ctx, e) })
+		if err != nil {
+			b.release()
+		} else {
+			// the slot outlives this call if the transformer timed out, see slot.
+			s := newSlot(b.release)
+			start := time.Now()
+			out, err = b.transform(contextWithSlot(ctx, s), t, v)
+			s.done()
+			err = applyPanicPolicy(b.cfg, err)
+
+			e.Out, e.Err, e.Duration = out, err, time.Since(start)
+			if oErr := observe(ctx, b.cfg, func() { b.obs.OnTransformEnd(ctx, e) }); oErr != nil && err == nil {
+				out, err = v, oErr
+			}
+		}
+		if err == ErrSkip {
+			oErr := observe(ctx, b.cfg, func() {
+				b.obs.OnSkip(ctx, SkipEvent{
+					Layer:       b.layer,
+					LayerPath:   b.path,
+					Index:       idT,
+					Transformer: b.names[idT],
+					Packet:      v,
+				})
+			})
+			if oErr != nil && oErr != ErrSkip {
+				err = oErr
+			}
+		}
 		w := &waiter{
 			idT:   idT,
 			idV:   idV,
@@ -164,7 +306,7 @@ func (b *packetBroadcaster) runTransformer(t Transformer, v MessagePacket, idT,
 		}
 		if err != nil {
 			if err != ErrSkip {
-				return err
+				return b.wrapErr(t, v, err)
 			}
 			w.skipped = true
 		}
@@ -181,6 +323,40 @@ func (b *packetBroadcaster) runTransformer(t Transformer, v MessagePacket, idT,
 	}
 }
 
+// transform runs the transformer, applying the TransformerTimeout config field if set.
+//
+// Panics from the transformer (or the source called by it) are recovered and returned as
+// a *TransformerPanicError.
+func (b *packetBroadcaster) transform(ctx context.Context, t Transformer, v MessagePacket) (out MessagePacket, err error) {
+	defer recoverPanic(ctx, &err)
+
+	if b.cfg == nil || b.cfg.TransformerTimeout <= 0 {
+		return t.Transform(ctx, v)
+	}
+	// transformers with their own timeout.
+	if _, ok := t.(*deadlineTransformer); ok {
+		return t.Transform(ctx, v)
+	}
+
+	return transformWithTimeout(ctx, b.cfg, t, v, b.cfg.TransformerTimeout, b.cfg.TimeoutPolicy)
+}
+
+// wrapErr attributes the transformer error via a *PipelineError.
+//
+// Context errors caused by the pipeline shutting down are returned as they are.
+func (b *packetBroadcaster) wrapErr(t Transformer, v MessagePacket, err error) error {
+	if ctxErr := b.ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+		return err
+	}
+
+	return &PipelineError{
+		Layer:       b.layer,
+		Transformer: transformerName(t),
+		Input:       v.Message,
+		Err:         err,
+	}
+}
+
 // byIdV sorts the waiter by idV in ascending order.
 type byIdV []*waiter
 
@@ -217,16 +393,18 @@ func (b *packetBroadcaster) processValues(ch <-chan *waiter) func() error {
 		for {
 			// first check if we can process any values with what we currently have.
 			sort.Sort(byIdV(waiterBuf))
-			for i, w := range waiterBuf {
-				if w.idV > localIdV {
-					break
+			for len(waiterBuf) > 0 && waiterBuf[0].idV <= localIdV {
+				w := waiterBuf[0]
+				// shrink via reslicing (array wont grow allot), the waiter is removed
+				// before running since runWaiter releases its slot in pWg.
+				waiterBuf = waiterBuf[1:]
+				if w.idV == localIdV {
+					localIdV++
 				}
 
 				if err = b.runWaiter(w); err != nil {
 					return err
 				}
-				// shrink via reslicing (array wont grow allot)
-				waiterBuf = waiterBuf[i+1:]
 			}
 
 			select {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -90,3 +91,107 @@ func notifyClose() (chan struct{}, handlerExit) {
 		close(ch)
 	}
 }
+
+type concurrencyTransformer struct {
+	running, max *int32
+	d            time.Duration
+}
+
+func (t concurrencyTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	n := atomic.AddInt32(t.running, 1)
+	defer atomic.AddInt32(t.running, -1)
+	for {
+		max := atomic.LoadInt32(t.max)
+		if n <= max || atomic.CompareAndSwapInt32(t.max, max, n) {
+			break
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return in, ctx.Err()
+	case <-time.After(t.d):
+		return in, nil
+	}
+}
+
+func TestBroadcasterConcurrencyLimit(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	for _, tc := range []struct {
+		name          string
+		layer, global int
+		want          int32
+	}{
+		{"Layer", 2, 0, 4}, // 2 layers.
+		{"Global", 0, 3, 3},
+		{"Both", 2, 3, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var running, max int32
+			tf := func(cfg *Config) (Transformer, bool) {
+				return concurrencyTransformer{&running, &max, 10 * time.Millisecond}, false
+			}
+
+			gen := New(&Config{
+				MaxBytes:            testConfig.MaxBytes,
+				MaxVals:             1000,
+				MaxChanges:          10,
+				MaxConcurrency:      tc.global,
+				MaxLayerConcurrency: tc.layer,
+				Source:              noopSource{true},
+			}).WithTransformers(tf, tf, tf, tf).WithTransformers(tf, tf, tf, tf)
+
+			vals, err := gen.Generate(context.Background(), "foo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(vals) != 16 {
+				t.Fatalf("expected 16 values but got %v", len(vals))
+			}
+			if max > tc.want {
+				t.Fatalf("expected at most %v transformers running but got %v", tc.want, max)
+			}
+		})
+	}
+}
+
+// ignoringTransformer ignores the context on purpose.
+type ignoringTransformer struct {
+	running, max *int32
+	d            time.Duration
+}
+
+func (t ignoringTransformer) Transform(_ context.Context, in MessagePacket) (MessagePacket, error) {
+	return concurrencyTransformer{t.running, t.max, t.d}.Transform(context.Background(), in)
+}
+
+func TestBroadcasterConcurrencyLimitTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	var running, max int32
+	tf := func(cfg *Config) (Transformer, bool) {
+		return ignoringTransformer{&running, &max, 30 * time.Millisecond}, false
+	}
+
+	gen := New(&Config{
+		MaxBytes:           testConfig.MaxBytes,
+		MaxVals:            1000,
+		MaxChanges:         10,
+		MaxConcurrency:     1,
+		TransformerTimeout: 5 * time.Millisecond,
+		TimeoutPolicy:      TimeoutPassthrough,
+		Source:             noopSource{true},
+	}).WithTransformers(tf, tf, tf, tf)
+
+	if _, err := gen.Generate(context.Background(), "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if n := gen.Timeouts(); n != 4 {
+		t.Fatalf("expected 4 timeouts but got %v", n)
+	}
+	// the timed out transformers keep their slot till they return.
+	if max > 1 {
+		t.Fatalf("expected at most 1 transformer running but got %v", max)
+	}
+}
@@ -3,6 +3,8 @@ package sinoname
 import (
 	"math/rand"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Config represents a config object accepted by sinoname.New(), sinoname.LayerFactor and sinoname.TransformerFactory .
@@ -22,6 +24,49 @@ type Config struct {
 	// Each modification by a transformer is marked as a change.
 	MaxChanges int
 
+	// MaxConcurrency limits the number of transformers running at the same time across
+	// all the layers of one Generate call. Zero means no limit.
+	MaxConcurrency int
+
+	// MaxLayerConcurrency limits the number of transformers running at the same time in
+	// each layer of one Generate call. Zero means no limit.
+	//
+	// Values still leave the layer in the order they came in, the limit only slows down
+	// how fast they are fanned out to the transformers.
+	MaxLayerConcurrency int
+
+	// MaxBatchConcurrency limits the number of values generated at the same time by
+	// GenerateBatch and GenerateStream. Zero means runtime.GOMAXPROCS(0).
+	//
+	// MaxConcurrency is shared by all the values of a batch.
+	MaxBatchConcurrency int
+
+	// TransformerTimeout, when greater then 0, bounds each Transform call in the pipeline.
+	// Transformers which dont return in time are handled according to TimeoutPolicy
+	// instead of holding their layer till the Generate context expires.
+	//
+	// Transformers wrapped via Timeout use their own timeout.
+	//
+	// A timed out transformer keeps its MaxConcurrency and MaxLayerConcurrency slots till its
+	// Transform call returns, with a limit set transformers which ignore the context slow
+	// down the pipeline instead of piling up go-routines.
+	TransformerTimeout time.Duration
+
+	// TimeoutPolicy decides what happens to a message whose transformer timed out.
+	// The default policy is TimeoutSkip.
+	TimeoutPolicy TimeoutPolicy
+
+	// PanicPolicy decides what happens when a transformer, the source, a layer callback or
+	// the observer panics. The default policy (PanicShutdown) shuts down the pipeline and
+	// Generate returns a *TransformerPanicError, PanicSkip skips the message which caused
+	// the panic.
+	PanicPolicy PanicPolicy
+
+	// Observer, if set, is notified about what happens in the pipeline (transformer calls,
+	// source calls, skips, timeouts, ...). It must be set before calling New since New wraps
+	// the Source to observe its calls.
+	Observer Observer
+
 	// PreventDefault prevents the default value from being read by the consumer.
 	PreventDefault bool
 
@@ -43,16 +88,38 @@ type Config struct {
 	// If StripNumbers isnt provided, the defualt stripNumbersASCII is used.
 	StripNumbers func(string) (string, string)
 
+	// Normalization normalizes the input before it enters the pipeline so that visually
+	// identical inputs (Renée precomposed and decomposed) generate the same values. The
+	// default NoNormalization leaves the input as is.
+	Normalization NormalizationForm
+
+	// StripMarks strips the combining marks (diacritics) from the input before it enters
+	// the pipeline: Zoë -> Zoe. The input is normalized to NFC if Normalization isnt set.
+	StripMarks bool
+
 	// Adjectives is a slice of adjectives to be used by suffix, prefix and circumfix transformers.
 	// Should be shuffled before referenced.
+	//
+	// Use PrefixFrom, SuffixFrom and CircumfixFrom to use a WordList per transformer instead.
 	Adjectives []string
 
+	// PageTokenKey signs the continuation tokens of GeneratePage so that clients cant forge
+	// or alter them. If empty a random key is used, the tokens are then only valid for the
+	// generator which issued them.
+	PageTokenKey []byte
+
 	// RandSrc is used for random opperations throughout the pipeline.
 	RandSrc *rand.Rand
+	// randMu guards the draws from RandSrc made via intn, a *rand.Rand isnt safe for
+	// concurrent use.
+	randMu sync.Mutex
 
 	// shuffle pool is non-nil if adjectives are provided, it keeps alive fixed sized
 	// buffers of shuffled integers used to shuffle the adjectives slice.
 	shufflePool sync.Pool
+
+	// timeouts counts the transformer calls which timed out.
+	timeouts atomic.Uint64
 }
 
 func (c *Config) getShuffle() []int {
@@ -72,3 +139,38 @@ func (c *Config) putShuffle(slc []int) {
 	c.RandSrc.Shuffle(len(slc), func(i, j int) { slc[i], slc[j] = slc[j], slc[i] })
 	c.shufflePool.Put(slc)
 }
+
+// intn returns a random number in [0, n) drawn from RandSrc, or from the global source
+// of math/rand if RandSrc is nil.
+func (c *Config) intn(n int) int {
+	if c == nil || c.RandSrc == nil {
+		return rand.Intn(n)
+	}
+
+	c.randMu.Lock()
+	defer c.randMu.Unlock()
+	return c.RandSrc.Intn(n)
+}
+
+// normalize normalizes the input according to the Normalization and StripMarks fields.
+func (c *Config) normalize(in string) string {
+	return normalize(in, c.Normalization, c.StripMarks)
+}
+
+// observer returns the configured observer or a NopObserver if there is none.
+func (c *Config) observer() Observer {
+	if c == nil || c.Observer == nil {
+		return NopObserver{}
+	}
+
+	return c.Observer
+}
+
+// source returns the source provided by the client, unwrapping the source installed by New.
+func (c *Config) source() Source {
+	if s, ok := c.Source.(*pipelineSource); ok {
+		return s.src
+	}
+
+	return c.Source
+}
@@ -20,7 +20,7 @@ type stringKey struct{}
 
 // ContextWithString adds a string value to the context, this value will be linked to the
 // value you are sending in the pipeline to generate more custom outcomes.
-func ContextWithString(ctx context.Context, v stringKey) context.Context {
+func ContextWithString(ctx context.Context, v string) context.Context {
 	return context.WithValue(ctx, stringKey{}, v)
 }
 
@@ -29,3 +29,101 @@ func StringFromContext(ctx context.Context) (string, bool) {
 	v, ok := ctx.Value(stringKey{}).(string)
 	return v, ok
 }
+
+type layerKey struct{}
+
+// contextWithLayer adds the index of the layer running in the pipeline to the context.
+//
+// The index is appended to the path of the enclosing layer, if any, so that the layers
+// nested in sub-pipelines, branches and routes get a unique path.
+func contextWithLayer(ctx context.Context, i int) context.Context {
+	parent := layerPathFromContext(ctx)
+	path := make([]int, len(parent)+1)
+	copy(path, parent)
+	path[len(parent)] = i
+	return context.WithValue(ctx, layerKey{}, path)
+}
+
+// layerFromContext gets the index of the top level layer from the context, -1 if unknown.
+func layerFromContext(ctx context.Context) int {
+	path := layerPathFromContext(ctx)
+	if len(path) == 0 {
+		return -1
+	}
+	return path[0]
+}
+
+// layerPathFromContext gets the path of the layer from the context, nil if unknown.
+func layerPathFromContext(ctx context.Context) []int {
+	path, _ := ctx.Value(layerKey{}).([]int)
+	return path
+}
+
+type transformerKey struct{}
+
+// transformerInfo identifies the transformer running in its layer.
+type transformerInfo struct {
+	index int
+	name  string
+	t     Transformer
+}
+
+// contextWithTransformer adds the index, name and the transformer running in its layer to
+// the context.
+func contextWithTransformer(ctx context.Context, i int, name string, t Transformer) context.Context {
+	return context.WithValue(ctx, transformerKey{}, transformerInfo{i, name, t})
+}
+
+// transformerFromContext gets the index of the transformer from the context, -1 if
+// unknown.
+func transformerFromContext(ctx context.Context) int {
+	info, ok := ctx.Value(transformerKey{}).(transformerInfo)
+	if !ok {
+		return -1
+	}
+	return info.index
+}
+
+// transformerNameFromContext gets the name of the transformer from the context, "" if
+// unknown.
+func transformerNameFromContext(ctx context.Context) string {
+	info, _ := ctx.Value(transformerKey{}).(transformerInfo)
+	return info.name
+}
+
+// transformerValueFromContext gets the transformer from the context, nil if unknown.
+func transformerValueFromContext(ctx context.Context) Transformer {
+	info, _ := ctx.Value(transformerKey{}).(transformerInfo)
+	return info.t
+}
+
+type sourceKey struct{}
+
+// contextWithSource overrides the configured source for the pipeline run with the context.
+func contextWithSource(ctx context.Context, src Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, src)
+}
+
+// sourceFromContext gets the source overriding the configured source from the context.
+func sourceFromContext(ctx context.Context) (Source, bool) {
+	src, ok := ctx.Value(sourceKey{}).(Source)
+	return src, ok
+}
+
+type observerKey struct{}
+
+// contextWithObserver overrides the configured observer for the pipeline run with the
+// context.
+func contextWithObserver(ctx context.Context, obs Observer) context.Context {
+	return context.WithValue(ctx, observerKey{}, obs)
+}
+
+// observerFor gets the observer notified by the pipeline run with the context, the observer
+// from the context if any or the configured observer.
+func observerFor(ctx context.Context, cfg *Config) Observer {
+	if obs, ok := ctx.Value(observerKey{}).(Observer); ok {
+		return obs
+	}
+
+	return cfg.observer()
+}
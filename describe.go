@@ -0,0 +1,274 @@
+package sinoname
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Param is a named parameter of a transformer or layer.
+type Param struct {
+	Name  string
+	Value string
+}
+
+// TransformerDescription describes a transformer.
+type TransformerDescription struct {
+	// Name is the name of the transformer, for built-in transformers it is the name of the
+	// factory creating it (for example NumbersSuffix).
+	Name string
+	// Params are the parameters the transformer was created with.
+	Params []Param
+	// Statefull indicates that the transformer is re-created on each Generate call.
+	Statefull bool
+}
+
+func (d TransformerDescription) String() string {
+	if len(d.Params) == 0 {
+		return d.Name
+	}
+
+	params := make([]string, len(d.Params))
+	for i, p := range d.Params {
+		params[i] = p.Name + "=" + p.Value
+	}
+	return d.Name + "(" + strings.Join(params, ", ") + ")"
+}
+
+// Describer is implemented by transformers which can describe themselves. All built-in
+// transformers implement Describer, transformers which dont are described by their type.
+type Describer interface {
+	Describe() TransformerDescription
+}
+
+// LayerDescription describes a layer.
+type LayerDescription struct {
+	// Type is the type of the layer, for example TransformerLayer.
+	Type string
+	// Label describes the role of the layer in its parent layer, for example the route
+	// of a RouterLayer.
+	Label string
+	// Params are the parameters the layer was created with.
+	Params []Param
+	// Transformers are the transformers held by the layer.
+	Transformers []TransformerDescription
+	// Layers are the sub-layers of layers composed from other layers (Layers, RouterLayer
+	// and ParallelLayer).
+	Layers []LayerDescription
+}
+
+// LayerDescriber is implemented by layers which can describe themselves. All built-in
+// layers implement LayerDescriber, layers which dont are described by their type.
+type LayerDescriber interface {
+	Describe() LayerDescription
+}
+
+// PipelineDescription describes all the layers of a generator.
+type PipelineDescription struct {
+	Layers []LayerDescription
+}
+
+// Describe returns a structured description of the pipeline of the generator.
+func (g *Generator) Describe() PipelineDescription {
+	return PipelineDescription{
+		Layers: g.layers.Describe().Layers,
+	}
+}
+
+// Describe describes the layers in order.
+func (s Layers) Describe() LayerDescription {
+	d := LayerDescription{
+		Type:   "Pipeline",
+		Layers: make([]LayerDescription, len(s)),
+	}
+	for i, l := range s {
+		d.Layers[i] = describeLayer(l)
+	}
+
+	return d
+}
+
+func describeLayer(l Layer) LayerDescription {
+	if d, ok := l.(LayerDescriber); ok {
+		return d.Describe()
+	}
+
+	return LayerDescription{
+		Type: fmt.Sprintf("%T", l),
+	}
+}
+
+func describeTransformer(t Transformer) TransformerDescription {
+	if d, ok := t.(Describer); ok {
+		return d.Describe()
+	}
+
+	return TransformerDescription{
+		Name: fmt.Sprintf("%T", t),
+	}
+}
+
+// DOT exports the description as a Graphviz DOT graph. Each layer is drawn as a cluster
+// and each transformer as a node connected to all the transformers of the next layer.
+func (d PipelineDescription) DOT() string {
+	g := d.graph()
+
+	var b strings.Builder
+	b.WriteString("digraph sinoname {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	g.root.writeDOT(&b, "\t")
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", e[0], e[1])
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Mermaid exports the description as a Mermaid flowchart. Each layer is drawn as a
+// subgraph and each transformer as a node connected to all the transformers of the next
+// layer.
+func (d PipelineDescription) Mermaid() string {
+	g := d.graph()
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	g.root.writeMermaid(&b, "\t")
+	for _, e := range g.edges {
+		fmt.Fprintf(&b, "\t%s --> %s\n", e[0], e[1])
+	}
+
+	return b.String()
+}
+
+// graph is the intermediate representation shared by the DOT and Mermaid exports.
+type graph struct {
+	root  graphCluster
+	edges [][2]string
+	n     int
+}
+
+type graphNode struct {
+	id    string
+	label string
+}
+
+type graphCluster struct {
+	id       string
+	label    string
+	nodes    []graphNode
+	clusters []*graphCluster
+}
+
+func (d PipelineDescription) graph() *graph {
+	g := &graph{}
+	in := g.node(&g.root, "in")
+	outs := g.pipeline(&g.root, d.Layers, []string{in})
+	out := g.node(&g.root, "out")
+	g.connect(outs, []string{out})
+
+	return g
+}
+
+func (g *graph) node(c *graphCluster, label string) string {
+	g.n++
+	id := "n" + strconv.Itoa(g.n)
+	c.nodes = append(c.nodes, graphNode{id, label})
+	return id
+}
+
+func (g *graph) cluster(parent *graphCluster, label string) *graphCluster {
+	g.n++
+	c := &graphCluster{
+		id:    "c" + strconv.Itoa(g.n),
+		label: label,
+	}
+	parent.clusters = append(parent.clusters, c)
+	return c
+}
+
+func (g *graph) connect(from, to []string) {
+	for _, f := range from {
+		for _, t := range to {
+			g.edges = append(g.edges, [2]string{f, t})
+		}
+	}
+}
+
+// pipeline adds the layers one after the other, the outputs of each layer are connected
+// to the inputs of the next one.
+func (g *graph) pipeline(parent *graphCluster, layers []LayerDescription, ins []string) []string {
+	for _, l := range layers {
+		ins = g.layer(parent, l, ins)
+	}
+
+	return ins
+}
+
+// layer adds the layer to the parent cluster connecting ins to the inputs of the layer,
+// it returns the outputs of the layer.
+func (g *graph) layer(parent *graphCluster, l LayerDescription, ins []string) []string {
+	label := l.Type
+	if l.Label != "" {
+		label = l.Label + ": " + label
+	}
+	if len(l.Params) > 0 {
+		label = TransformerDescription{Name: label, Params: l.Params}.String()
+	}
+	c := g.cluster(parent, label)
+
+	switch {
+	case len(l.Transformers) > 0:
+		var outs []string
+		for _, t := range l.Transformers {
+			outs = append(outs, g.node(c, t.String()))
+		}
+		g.connect(ins, outs)
+		return outs
+
+	case l.Type == "Pipeline":
+		return g.pipeline(c, l.Layers, ins)
+
+	case len(l.Layers) > 0: // branching layers.
+		var outs []string
+		for _, sub := range l.Layers {
+			outs = append(outs, g.layer(c, sub, ins)...)
+		}
+		return outs
+
+	default:
+		out := g.node(c, l.Type)
+		g.connect(ins, []string{out})
+		return []string{out}
+	}
+}
+
+func (c *graphCluster) writeDOT(b *strings.Builder, indent string) {
+	for _, n := range c.nodes {
+		fmt.Fprintf(b, "%s%s [label=%s];\n", indent, n.id, quoteDOT(n.label))
+	}
+	for _, sub := range c.clusters {
+		fmt.Fprintf(b, "%ssubgraph cluster_%s {\n", indent, sub.id)
+		fmt.Fprintf(b, "%s\tlabel=%s;\n", indent, quoteDOT(sub.label))
+		sub.writeDOT(b, indent+"\t")
+		fmt.Fprintf(b, "%s}\n", indent)
+	}
+}
+
+func (c *graphCluster) writeMermaid(b *strings.Builder, indent string) {
+	for _, n := range c.nodes {
+		fmt.Fprintf(b, "%s%s[%s]\n", indent, n.id, quoteMermaid(n.label))
+	}
+	for _, sub := range c.clusters {
+		fmt.Fprintf(b, "%ssubgraph %s [%s]\n", indent, sub.id, quoteMermaid(sub.label))
+		sub.writeMermaid(b, indent+"\t")
+		fmt.Fprintf(b, "%send\n", indent)
+	}
+}
+
+func quoteDOT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func quoteMermaid(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s) + `"`
+}
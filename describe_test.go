@@ -0,0 +1,111 @@
+package sinoname
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	gen := New(&Config{
+		MaxBytes: testConfig.MaxBytes,
+		MaxVals:  testConfig.MaxVals,
+		Source:   noopSource{true},
+	}).WithTransformers(
+		NumbersSuffix("_"),
+		Timeout(time.Second, TimeoutPassthrough, Plural),
+		newStatefullTransformer(),
+	).WithUniformTransformers(
+		CamelCase,
+	).WithLayers(
+		Router(nil, Route{
+			Name:  "short",
+			Match: func(context.Context, MessagePacket) bool { return true },
+			Layer: Pipeline(Transformers(SymbolTransformer('.', 2)), Dedup),
+		}),
+		Parallel(Branch{Layer: Transformers(Noop), MaxVals: 3}),
+	)
+
+	t.Run("Structure", func(t *testing.T) {
+		d := gen.Describe()
+		if len(d.Layers) != 4 {
+			t.Fatalf("expected 4 layers got %v", len(d.Layers))
+		}
+
+		first := d.Layers[0]
+		if first.Type != "TransformerLayer" {
+			t.Fatalf("expected TransformerLayer got %v", first.Type)
+		}
+		want := []TransformerDescription{
+			{Name: "NumbersSuffix", Params: []Param{{"sep", `"_"`}}},
+			{Name: "Plural", Params: []Param{{"timeout", "1s"}, {"policy", "TimeoutPassthrough"}}},
+			{Name: "*sinoname.statefullTransformer", Statefull: true},
+		}
+		if !reflect.DeepEqual(first.Transformers, want) {
+			t.Fatalf("expected %v got %v", want, first.Transformers)
+		}
+
+		if d.Layers[1].Type != "UniformTransformerLayer" || d.Layers[1].Transformers[0].Name != "CamelCase" {
+			t.Fatalf("unexpected uniform layer description %v", d.Layers[1])
+		}
+
+		route := d.Layers[2].Layers[0]
+		if route.Label != "short" || route.Type != "Pipeline" || len(route.Layers) != 2 {
+			t.Fatalf("unexpected route description %v", route)
+		}
+		if route.Layers[0].Transformers[0].String() != "SymbolTransformer(symbol='.', max=2)" {
+			t.Fatalf("unexpected symbol transformer description %v", route.Layers[0].Transformers[0])
+		}
+		if route.Layers[1].Type != "DedupLayer" {
+			t.Fatalf("expected DedupLayer got %v", route.Layers[1].Type)
+		}
+
+		branch := d.Layers[3].Layers[0]
+		if branch.Label != "branch 0" || !reflect.DeepEqual(branch.Params, []Param{{"MaxVals", "3"}}) {
+			t.Fatalf("unexpected branch description %v", branch)
+		}
+	})
+
+	t.Run("Describe_After_Generate", func(t *testing.T) {
+		before := gen.Describe()
+		if _, err := gen.Generate(context.Background(), "foo"); err != nil {
+			t.Fatal(err)
+		}
+
+		if after := gen.Describe(); !reflect.DeepEqual(before, after) {
+			t.Fatalf("expected %v got %v", before, after)
+		}
+	})
+
+	t.Run("DOT", func(t *testing.T) {
+		dot := gen.Describe().DOT()
+		for _, s := range []string{
+			"digraph sinoname {",
+			`label="TransformerLayer";`,
+			`[label="NumbersSuffix(sep=\"_\")"];`,
+			`label="short: Pipeline";`,
+			`label="branch 0: TransformerLayer(MaxVals=3)";`,
+			"n1 -> n3;",
+		} {
+			if !strings.Contains(dot, s) {
+				t.Fatalf("expected %q in:\n%v", s, dot)
+			}
+		}
+	})
+
+	t.Run("Mermaid", func(t *testing.T) {
+		mermaid := gen.Describe().Mermaid()
+		for _, s := range []string{
+			"flowchart LR",
+			`n3["NumbersSuffix(sep=#quot;_#quot;)"]`,
+			`subgraph c2 ["TransformerLayer"]`,
+			"n1 --> n3",
+		} {
+			if !strings.Contains(mermaid, s) {
+				t.Fatalf("expected %q in:\n%v", s, mermaid)
+			}
+		}
+	})
+}
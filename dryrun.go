@@ -0,0 +1,230 @@
+package sinoname
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+)
+
+// AlwaysValid is a Source which accepts every value. Used with DryRun it measures the best
+// case of the pipeline: each transformer stops at its first candidate.
+var AlwaysValid Source = staticSource(true)
+
+// AlwaysTaken is a Source which rejects every value. Used with DryRun it measures the worst
+// case of the pipeline: each transformer tries all of its candidates.
+var AlwaysTaken Source = staticSource(false)
+
+type staticSource bool
+
+func (s staticSource) Valid(context.Context, string) (bool, error) {
+	return bool(s), nil
+}
+
+// Estimator is implemented by transformers which can analytically estimate the number of
+// candidates they try for a message, that is the maximum number of source calls they make
+// when all the candidates are taken.
+//
+// Estimates which dont fit in an int are capped to math.MaxInt.
+type Estimator interface {
+	Estimate(ctx context.Context, in MessagePacket) int
+}
+
+// TransformerEstimate holds the analytic estimate of one transformer collected by DryRun.
+type TransformerEstimate struct {
+	Layer       int
+	LayerPath   []int
+	Index       int
+	Transformer string
+
+	// Candidates is the sum of the estimates of all the messages processed by the
+	// transformer.
+	Candidates int
+}
+
+// DryRunReport describes the run of the pipeline for one input.
+type DryRunReport struct {
+	// Input is the value passed to DryRun.
+	Input string
+	// Values is the number of values which reached the end of the pipeline, MaxVals is
+	// ignored by dry runs.
+	Values int
+	// Distinct is the number of distinct values, other then the input, which reached the end
+	// of the pipeline.
+	Distinct int
+	// SourceCalls is the total number of source calls.
+	SourceCalls int
+
+	// Layers holds the stats of each layer.
+	Layers []LayerStats
+	// Transformers holds the stats of each transformer.
+	Transformers []TransformerStats
+	// Estimates holds the analytic estimates of the transformers implementing Estimator.
+	Estimates []TransformerEstimate
+}
+
+// DryRun passes the in field through the pipeline validating all the values against src
+// instead of the configured source, usually AlwaysValid or AlwaysTaken. Unlike Generate
+// all the values sent out by the pipeline are read.
+//
+// The events of the dry run arent reported to the configured observer.
+//
+//	report, err := gen.DryRun(ctx, "lambels", sinoname.AlwaysTaken)
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Printf("%d candidates, %d source calls worst case\n", report.Distinct, report.SourceCalls)
+func (g *Generator) DryRun(ctx context.Context, in string, src Source) (DryRunReport, error) {
+	in = g.cfg.normalize(in)
+	report := DryRunReport{
+		Input: in,
+	}
+	if len(in) > g.cfg.MaxBytes {
+		return report, ErrTooLong
+	}
+
+	obs := newDryRunObserver()
+	ctx = contextWithSource(ctx, src)
+	ctx = contextWithObserver(ctx, obs)
+	ctx = contextWithRandomNumber(ctx, g.cfg)
+	ctx = contextWithSemaphore(ctx, newSemaphore(g.cfg.MaxConcurrency))
+	inC, clnUp, err := g.layers.Run(ctx, MessagePacket{Message: in})
+	if err != nil {
+		clnUp()
+		return report, err
+	}
+
+	seen := map[string]struct{}{
+		in: {},
+	}
+L:
+	for {
+		select {
+		case <-ctx.Done():
+			clnUp()
+			return report, ctx.Err()
+
+		case v, ok := <-inC:
+			if !ok {
+				break L
+			}
+
+			report.Values++
+			if _, ok := seen[v.Message]; !ok {
+				seen[v.Message] = struct{}{}
+				report.Distinct++
+			}
+		}
+	}
+	if err := clnUp(); err != nil {
+		return report, err
+	}
+
+	report.Layers = obs.Layers()
+	report.Transformers = obs.Transformers()
+	report.Estimates = obs.estimates()
+	for _, t := range report.Transformers {
+		report.SourceCalls += t.SourceCalls
+	}
+
+	return report, nil
+}
+
+// dryRunObserver collects the stats of a dry run along side the estimates of the
+// transformers.
+type dryRunObserver struct {
+	*StatsObserver
+
+	mu        sync.Mutex
+	estimated map[transformerStatsKey]*TransformerEstimate
+}
+
+func newDryRunObserver() *dryRunObserver {
+	return &dryRunObserver{
+		StatsObserver: NewStatsObserver(),
+		estimated:     make(map[transformerStatsKey]*TransformerEstimate),
+	}
+}
+
+func (o *dryRunObserver) OnTransformStart(ctx context.Context, e TransformEvent) {
+	n, ok := estimate(ctx, transformerValueFromContext(ctx), e.In)
+	if !ok {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := transformerStatsKey{pathKey(e.LayerPath), e.Index}
+	est, ok := o.estimated[key]
+	if !ok {
+		est = &TransformerEstimate{
+			Layer:       e.Layer,
+			LayerPath:   append([]int(nil), e.LayerPath...),
+			Index:       e.Index,
+			Transformer: e.Transformer,
+		}
+		o.estimated[key] = est
+	}
+	est.Candidates = addSat(est.Candidates, n)
+}
+
+func (o *dryRunObserver) estimates() []TransformerEstimate {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	out := make([]TransformerEstimate, 0, len(o.estimated))
+	for _, e := range o.estimated {
+		out = append(out, *e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if c := comparePaths(out[i].LayerPath, out[j].LayerPath); c != 0 {
+			return c < 0
+		}
+		return out[i].Index < out[j].Index
+	})
+
+	return out
+}
+
+// estimate estimates the candidates of the transformer if it implements Estimator.
+func estimate(ctx context.Context, t Transformer, in MessagePacket) (int, bool) {
+	if d, ok := t.(*deadlineTransformer); ok {
+		t = d.t
+	}
+
+	e, ok := t.(Estimator)
+	if !ok {
+		return 0, false
+	}
+	return e.Estimate(ctx, in), true
+}
+
+// addSat adds a and b capping the result to math.MaxInt.
+func addSat(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// mulSat multiplies a and b capping the result to math.MaxInt.
+func mulSat(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+// binomialSat returns n choose k capping the result to math.MaxInt.
+func binomialSat(n, k int) int {
+	c := 1.0
+	for i := 1; i <= k; i++ {
+		c = c * float64(n-k+i) / float64(i)
+	}
+	if c >= math.MaxInt {
+		return math.MaxInt
+	}
+
+	return int(math.Round(c))
+}
@@ -0,0 +1,147 @@
+package sinoname
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestDryRun(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	src := &countSource{}
+	stats := NewStatsObserver()
+	gen := New(&Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    1,
+		MaxChanges: 10,
+		Source:     src,
+		Observer:   stats,
+	}).WithTransformers(
+		SymbolTransformer('.', 0),
+		IncrementalSuffix(3, "_"),
+		ShuffleOrder("_"),
+	)
+
+	t.Run("Always_Taken", func(t *testing.T) {
+		report, err := gen.DryRun(context.Background(), "abc", AlwaysTaken)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// C(4, 1) + C(4, 2) + C(4, 3) + 3 + 1!
+		if report.SourceCalls != 18 {
+			t.Fatalf("expected 18 source calls got %v", report.SourceCalls)
+		}
+		if report.Values != 3 || report.Distinct != 0 {
+			t.Fatalf("expected 3 values and 0 distinct values got %v and %v", report.Values, report.Distinct)
+		}
+
+		want := []int{14, 3, 1}
+		if len(report.Estimates) != len(want) {
+			t.Fatalf("expected %v estimates got %v", len(want), len(report.Estimates))
+		}
+		for i, e := range report.Estimates {
+			if e.Candidates != want[i] || e.Candidates != report.Transformers[i].SourceCalls {
+				t.Fatalf("expected %v candidates got %v (%v source calls)", want[i], e.Candidates, report.Transformers[i].SourceCalls)
+			}
+		}
+	})
+
+	t.Run("Always_Valid", func(t *testing.T) {
+		report, err := gen.DryRun(context.Background(), "abc", AlwaysValid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// MaxVals is ignored and the shuffled value is the input.
+		if report.SourceCalls != 3 || report.Values != 3 || report.Distinct != 2 {
+			t.Fatalf("expected 3 source calls, 3 values and 2 distinct values got %v", report)
+		}
+		if len(report.Layers) != 1 || report.Layers[0].Values != 1 {
+			t.Fatalf("unexpected layer stats %v", report.Layers)
+		}
+	})
+
+	t.Run("Isolated", func(t *testing.T) {
+		if src.n != 0 {
+			t.Fatalf("expected no calls to the configured source got %v", src.n)
+		}
+		if len(stats.Transformers()) != 0 || len(stats.Layers()) != 0 {
+			t.Fatal("expected no events reported to the configured observer")
+		}
+	})
+
+	t.Run("No_Source", func(t *testing.T) {
+		gen := New(&Config{
+			MaxBytes:   testConfig.MaxBytes,
+			MaxVals:    1,
+			MaxChanges: 10,
+		}).WithTransformers(IncrementalSuffix(3, "_"))
+
+		report, err := gen.DryRun(context.Background(), "abc", AlwaysTaken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.SourceCalls != 3 {
+			t.Fatalf("expected 3 source calls got %v", report.SourceCalls)
+		}
+	})
+
+	t.Run("Too_Long", func(t *testing.T) {
+		gen := New(&Config{
+			MaxBytes: 2,
+			Source:   src,
+		}).WithTransformers(Noop)
+
+		if _, err := gen.DryRun(context.Background(), "abc", AlwaysValid); err != ErrTooLong {
+			t.Fatalf("expected %v got %v", ErrTooLong, err)
+		}
+	})
+}
+
+func TestEstimate(t *testing.T) {
+	cfg := &Config{
+		MaxBytes:   6,
+		Tokenize:   tokenizeDefault,
+		Adjectives: []string{"big", "tiny", "gigantic"},
+	}
+	build := func(tFact TransformerFactory) Estimator {
+		tr, _ := tFact(cfg)
+		return tr.(Estimator)
+	}
+	ctx := ContextWithNumber(context.Background(), 7)
+
+	for _, tc := range []struct {
+		name string
+		e    Estimator
+		in   string
+		want int
+	}{
+		// 1 symbol: C(5, 1), 2 symbols: C(5, 2), 3 symbols dont fit.
+		{"Symbol", build(SymbolTransformer('.', 0)), "abcd", 15},
+		{"Symbol_Max", build(SymbolTransformer('.', 1)), "abcd", 5},
+		{"Shuffle_Order", build(ShuffleOrder("")), "a.b.c", 6},
+		// abcd_1 ... abcd_9 fit, abcd_10 doesent.
+		{"Incremental", build(IncrementalSuffix(20, "_")), "abcd", 9},
+		{"Numbers", build(NumbersSuffix("")), "abc", 2},
+		{"Affix", build(Suffix("")), "ab", 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.e.Estimate(ctx, MessagePacket{Message: tc.in}); got != tc.want {
+				t.Fatalf("expected %v got %v", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("Saturated", func(t *testing.T) {
+		if got := binomialSat(200, 100); got != math.MaxInt {
+			t.Fatalf("expected %v got %v", math.MaxInt, got)
+		}
+		if got := mulSat(math.MaxInt/2, 3); got != math.MaxInt {
+			t.Fatalf("expected %v got %v", math.MaxInt, got)
+		}
+	})
+}
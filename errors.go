@@ -0,0 +1,134 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+var (
+	// ErrNoLayers is returned when running a generator (or a pipeline) without layers.
+	ErrNoLayers = errors.New("sinoname: generator has no layers")
+	// ErrNoTransformers is returned when running a layer without transformers.
+	ErrNoTransformers = errors.New("sinoname: layer has no transformers")
+	// ErrNoBranches is returned when running a ParallelLayer without branches.
+	ErrNoBranches = errors.New("sinoname: layer has no branches")
+	// ErrTooLong is returned when the value passed to Generate is longer then MaxBytes.
+	ErrTooLong = errors.New("sinoname: value is too long")
+	// ErrInvalidToken is returned when the continuation token passed to GeneratePage is
+	// malformed, altered or belongs to another value.
+	ErrInvalidToken = errors.New("sinoname: invalid continuation token")
+	// ErrPageLimit is returned by GeneratePage when the continuation token holds the
+	// maximum number of values, fetching more pages could repeat values.
+	ErrPageLimit = errors.New("sinoname: continuation token is full")
+)
+
+// PipelineError is returned when a transformer (or the source called by it) fails. It
+// records where in the pipeline the error happened and wraps the original error.
+//
+//	var perr *sinoname.PipelineError
+//	if errors.As(err, &perr) {
+//		log.Printf("layer %d, %s failed on %q", perr.Layer, perr.Transformer, perr.Input)
+//	}
+type PipelineError struct {
+	// Layer is the index of the top level layer in the pipeline running it, -1 if unknown.
+	Layer int
+	// Transformer is the name of the failing transformer.
+	Transformer string
+	// Input is the message the transformer was processing.
+	Input string
+	// Err is the error returned by the transformer.
+	Err error
+}
+
+func (e *PipelineError) Error() string {
+	return fmt.Sprintf("sinoname: layer %d, %s on %q: %v", e.Layer, e.Transformer, e.Input, e.Err)
+}
+
+func (e *PipelineError) Unwrap() error {
+	return e.Err
+}
+
+// transformerName gets a printable name for the transformer.
+func transformerName(t Transformer) string {
+	return describeTransformer(t).Name
+}
+
+// PanicPolicy decides what happens when user code (a transformer, the source, a layer
+// callback or the observer) panics while running in the pipeline.
+type PanicPolicy int
+
+const (
+	// PanicShutdown shuts down the pipeline, Generate returns a *TransformerPanicError.
+	PanicShutdown PanicPolicy = iota
+	// PanicSkip skips the message which caused the panic and keeps the pipeline running.
+	PanicSkip
+)
+
+// TransformerPanicError is the error produced when a transformer (or the source called by
+// it) panics. Panics in layer callbacks, like Router predicates or Filter functions, are
+// also reported via TransformerPanicError with the Transformer field set to -1, panics in
+// the Observer methods with the indexes of the layer and transformer being observed.
+type TransformerPanicError struct {
+	// Layer is the index of the top level layer in the pipeline running it, -1 if unknown.
+	Layer int
+	// Transformer is the index of the transformer in its layer, -1 if unknown.
+	Transformer int
+	// Value is the value passed to panic.
+	Value any
+	// Stack is the stack trace of the panicking go-routine.
+	Stack []byte
+}
+
+func (e *TransformerPanicError) Error() string {
+	return fmt.Sprintf("sinoname: panic in layer %d transformer %d: %v", e.Layer, e.Transformer, e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error.
+func (e *TransformerPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverPanic recovers a panic converting it into a *TransformerPanicError stored in err,
+// the layer and transformer indexes are taken from the context.
+//
+// recoverPanic must be deferred directly:
+//
+//	defer recoverPanic(ctx, &err)
+func recoverPanic(ctx context.Context, err *error) {
+	if p := recover(); p != nil {
+		*err = &TransformerPanicError{
+			Layer:       layerFromContext(ctx),
+			Transformer: transformerFromContext(ctx),
+			Value:       p,
+			Stack:       debug.Stack(),
+		}
+	}
+}
+
+// observe calls fn, which calls a method of the observer, recovering its panics like the
+// panics of the transformers: the panic is converted into a *TransformerPanicError and
+// handled according to the PanicPolicy config field.
+func observe(ctx context.Context, cfg *Config, fn func()) (err error) {
+	defer func() { err = applyPanicPolicy(cfg, err) }()
+	defer recoverPanic(ctx, &err)
+
+	fn()
+	return nil
+}
+
+// applyPanicPolicy replaces panic errors with ErrSkip if the config asks to skip the
+// messages which caused a panic. Any other error is returned as is.
+func applyPanicPolicy(cfg *Config, err error) error {
+	if err == nil || cfg == nil || cfg.PanicPolicy != PanicSkip {
+		return err
+	}
+
+	var perr *TransformerPanicError
+	if errors.As(err, &perr) {
+		return ErrSkip
+	}
+	return err
+}
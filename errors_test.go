@@ -0,0 +1,172 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type panicTransformer struct{}
+
+func (t panicTransformer) Transform(context.Context, MessagePacket) (MessagePacket, error) {
+	panic("transformer panic")
+}
+
+func newPanicTransformer() TransformerFactory {
+	return func(cfg *Config) (Transformer, bool) {
+		return panicTransformer{}, false
+	}
+}
+
+type panicSource struct{}
+
+func (s panicSource) Valid(context.Context, string) (bool, error) {
+	panic("source panic")
+}
+
+func TestPanicIsolation(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	newConfig := func(policy PanicPolicy) *Config {
+		return &Config{
+			MaxBytes:    testConfig.MaxBytes,
+			MaxVals:     testConfig.MaxVals,
+			MaxChanges:  10,
+			Source:      noopSource{true},
+			PanicPolicy: policy,
+		}
+	}
+
+	t.Run("Shutdown", func(t *testing.T) {
+		gen := New(newConfig(PanicShutdown)).WithTransformers(
+			Noop,
+		).WithTransformers(
+			newAddTransformer("a"),
+			newPanicTransformer(),
+		)
+
+		_, err := gen.Generate(context.Background(), "foo")
+		var perr *TransformerPanicError
+		if !errors.As(err, &perr) {
+			t.Fatalf("expected *TransformerPanicError but got %v", err)
+		}
+		if perr.Layer != 1 || perr.Transformer != 1 || perr.Value != "transformer panic" || len(perr.Stack) == 0 {
+			t.Fatalf("unexpected panic error: %#v", perr)
+		}
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		gen := New(newConfig(PanicSkip)).WithTransformers(
+			newAddTransformer("a"),
+			newPanicTransformer(),
+		)
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != 1 || vals[0] != "fooa" {
+			t.Fatalf("expected [fooa] but got %v", vals)
+		}
+	})
+
+	t.Run("Source", func(t *testing.T) {
+		cfg := newConfig(PanicShutdown)
+		cfg.Source = panicSource{}
+		gen := New(cfg).WithTransformers(Plural)
+
+		_, err := gen.Generate(context.Background(), "foo")
+		var perr *TransformerPanicError
+		if !errors.As(err, &perr) || perr.Value != "source panic" {
+			t.Fatalf("expected source *TransformerPanicError but got %v", err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		gen := New(newConfig(PanicShutdown)).WithTransformers(
+			Timeout(time.Second, TimeoutSkip, newPanicTransformer()),
+		)
+
+		_, err := gen.Generate(context.Background(), "foo")
+		var perr *TransformerPanicError
+		if !errors.As(err, &perr) || perr.Layer != 0 || perr.Transformer != 0 {
+			t.Fatalf("expected *TransformerPanicError but got %v", err)
+		}
+	})
+
+	t.Run("Router", func(t *testing.T) {
+		gen := New(newConfig(PanicShutdown)).WithLayers(Router(
+			nil,
+			Route{
+				Match: func(context.Context, MessagePacket) bool { panic("predicate panic") },
+				Layer: Transformers(Noop),
+			},
+		))
+
+		_, err := gen.Generate(context.Background(), "foo")
+		var perr *TransformerPanicError
+		if !errors.As(err, &perr) || perr.Transformer != -1 || perr.Value != "predicate panic" {
+			t.Fatalf("expected predicate *TransformerPanicError but got %v", err)
+		}
+	})
+}
+
+type errSource struct {
+	err error
+}
+
+func (s errSource) Valid(context.Context, string) (bool, error) {
+	return false, s.err
+}
+
+func TestPipelineError(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errDB := errors.New("database down")
+	gen := New(&Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    testConfig.MaxVals,
+		MaxChanges: 10,
+		Source:     errSource{errDB},
+	}).WithTransformers(
+		Noop,
+	).WithTransformers(
+		Plural,
+	)
+
+	_, err := gen.Generate(context.Background(), "foo")
+	if !errors.Is(err, errDB) {
+		t.Fatalf("expected error to wrap the source error but got %v", err)
+	}
+
+	var perr *PipelineError
+	if !errors.As(err, &perr) {
+		t.Fatalf("expected *PipelineError but got %v", err)
+	}
+	if perr.Layer != 1 || perr.Input != "foo" || perr.Transformer != "Plural" {
+		t.Fatalf("unexpected pipeline error: %#v", perr)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	cfg := &Config{
+		MaxBytes: 3,
+		MaxVals:  testConfig.MaxVals,
+		Source:   noopSource{true},
+	}
+
+	if _, err := New(cfg).Generate(context.Background(), "foo"); !errors.Is(err, ErrNoLayers) {
+		t.Fatalf("expected ErrNoLayers but got %v", err)
+	}
+	if _, err := New(cfg).WithTransformers().Generate(context.Background(), "foo"); !errors.Is(err, ErrNoTransformers) {
+		t.Fatalf("expected ErrNoTransformers but got %v", err)
+	}
+	if _, err := New(cfg).WithTransformers(Noop).Generate(context.Background(), "fooo"); !errors.Is(err, ErrTooLong) {
+		t.Fatalf("expected ErrTooLong but got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package sinoname
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// fanIn merges all the channels into one channel.
+//
+// The returned channel is closed once all the merged channels are closed or the context
+// is cancelled. All the go-routines are ran in g.
+func fanIn(ctx context.Context, g *errgroup.Group, chans ...<-chan MessagePacket) <-chan MessagePacket {
+	outC := make(chan MessagePacket)
+
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+	for _, ch := range chans {
+		ch := ch
+		g.Go(func() error {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+
+				case v, ok := <-ch:
+					if !ok {
+						return nil
+					}
+
+					select {
+					case <-ctx.Done():
+						return nil
+					case outC <- v:
+					}
+				}
+			}
+		})
+	}
+
+	g.Go(func() error {
+		wg.Wait()
+		close(outC)
+		return nil
+	})
+
+	return outC
+}
@@ -0,0 +1,262 @@
+package sinoname
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UserHints holds metadata about the user the values are generated for, transformers use
+// it to generate more personal values. All the fields are optional.
+type UserHints struct {
+	FirstName string
+	LastName  string
+	// BirthYear is the full year of birth (for example 1998), 0 if unknown.
+	BirthYear int
+	// Birthday is the date of birth, the zero time if unknown.
+	Birthday time.Time
+	// Locale is the BCP 47 language tag of the user (for example en-US).
+	Locale   string
+	Location string
+	// Interests of the user (for example chess, hiking, ...).
+	Interests []string
+	Email     string
+}
+
+type hintsKey struct{}
+
+// ContextWithHints adds the user hints to the context, the hints will be linked to the
+// value you are sending in the pipeline to generate more custom outcomes.
+func ContextWithHints(ctx context.Context, h UserHints) context.Context {
+	return context.WithValue(ctx, hintsKey{}, h)
+}
+
+// HintsFromContext gets the user hints from the context.
+func HintsFromContext(ctx context.Context) (UserHints, bool) {
+	h, ok := ctx.Value(hintsKey{}).(UserHints)
+	return h, ok
+}
+
+// birthYear returns the birth year from BirthYear or Birthday.
+func (h UserHints) birthYear() (int, bool) {
+	if h.BirthYear > 0 {
+		return h.BirthYear, true
+	}
+	if !h.Birthday.IsZero() {
+		return h.Birthday.Year(), true
+	}
+
+	return 0, false
+}
+
+// NumberHint selects a number used by the numbers transformers.
+type NumberHint int
+
+const (
+	// HintNumber selects the number added via ContextWithNumber.
+	HintNumber NumberHint = iota
+	// HintBirthYear selects the birth year from the user hints: 1998.
+	HintBirthYear
+	// HintShortBirthYear selects the last 2 digits of the birth year from the user
+	// hints: 98.
+	HintShortBirthYear
+	// HintBirthday selects the day and month of the birthday from the user hints, ordered
+	// by the locale: 0423 for en-US, 2304 otherwise.
+	HintBirthday
+	// HintRandomNumber selects a random number between 1 and 99 drawn from the RandSrc
+	// config field. The number is drawn once per Generate call, all the transformers of
+	// the call (and their estimates) see the same number.
+	HintRandomNumber
+)
+
+func (h NumberHint) String() string {
+	switch h {
+	case HintNumber:
+		return "Number"
+	case HintBirthYear:
+		return "BirthYear"
+	case HintShortBirthYear:
+		return "ShortBirthYear"
+	case HintBirthday:
+		return "Birthday"
+	case HintRandomNumber:
+		return "RandomNumber"
+	default:
+		return "NumberHint(" + strconv.Itoa(int(h)) + ")"
+	}
+}
+
+// number gets the number selected by the hint from the context.
+func (h NumberHint) number(ctx context.Context, cfg *Config) (string, bool) {
+	if h == HintNumber {
+		n, ok := NumberFromContext(ctx)
+		return strconv.Itoa(n), ok
+	}
+	if h == HintRandomNumber {
+		if r, ok := ctx.Value(randomNumberKey{}).(*randomNumber); ok {
+			return strconv.Itoa(r.get()), true
+		}
+		return strconv.Itoa(cfg.intn(99) + 1), true
+	}
+
+	hints, ok := HintsFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	switch h {
+	case HintBirthYear:
+		year, ok := hints.birthYear()
+		return strconv.Itoa(year), ok
+
+	case HintShortBirthYear:
+		year, ok := hints.birthYear()
+		return fmt.Sprintf("%02d", year%100), ok
+
+	case HintBirthday:
+		if hints.Birthday.IsZero() {
+			return "", false
+		}
+
+		if strings.EqualFold(hints.Locale, "en-US") {
+			return hints.Birthday.Format("0102"), true
+		}
+		return hints.Birthday.Format("0201"), true
+	}
+
+	return "", false
+}
+
+// StringHint selects the strings used by the Prefix, Suffix and Circumfix transformers.
+type StringHint int
+
+const (
+	// HintString selects the string added via ContextWithString.
+	HintString StringHint = iota
+	// HintFirstName selects the first name from the user hints.
+	HintFirstName
+	// HintLastName selects the last name from the user hints.
+	HintLastName
+	// HintLocation selects the location from the user hints.
+	HintLocation
+	// HintInterests selects each of the interests from the user hints.
+	HintInterests
+	// HintEmail selects the local part of the email from the user hints (lambels for
+	// lambels@example.com).
+	HintEmail
+)
+
+func (h StringHint) String() string {
+	switch h {
+	case HintString:
+		return "String"
+	case HintFirstName:
+		return "FirstName"
+	case HintLastName:
+		return "LastName"
+	case HintLocation:
+		return "Location"
+	case HintInterests:
+		return "Interests"
+	case HintEmail:
+		return "Email"
+	default:
+		return "StringHint(" + strconv.Itoa(int(h)) + ")"
+	}
+}
+
+// strings gets the strings selected by the hint from the context.
+func (h StringHint) strings(ctx context.Context) []string {
+	if h == HintString {
+		if v, ok := StringFromContext(ctx); ok {
+			return []string{v}
+		}
+		return nil
+	}
+
+	hints, ok := HintsFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var out []string
+	switch h {
+	case HintFirstName:
+		out = []string{hints.FirstName}
+	case HintLastName:
+		out = []string{hints.LastName}
+	case HintLocation:
+		out = []string{hints.Location}
+	case HintInterests:
+		out = hints.Interests
+	case HintEmail:
+		local, _, _ := strings.Cut(hints.Email, "@")
+		out = []string{local}
+	}
+
+	// drop the unknown hints.
+	filtered := out[:0:0]
+	for _, v := range out {
+		if v != "" {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+// hintNumbers gets the numbers selected by the hints, in order.
+func hintNumbers(ctx context.Context, cfg *Config, hints []NumberHint) []string {
+	var out []string
+	for _, h := range hints {
+		if v, ok := h.number(ctx, cfg); ok {
+			out = append(out, v)
+		}
+	}
+
+	return out
+}
+
+// hintStrings gets the strings selected by the hints, in order.
+func hintStrings(ctx context.Context, hints []StringHint) []string {
+	var out []string
+	for _, h := range hints {
+		out = append(out, h.strings(ctx)...)
+	}
+
+	return out
+}
+
+// joinHints formats the hints for the transformer descriptions.
+func joinHints[T fmt.Stringer](hints []T) string {
+	out := make([]string, len(hints))
+	for i, h := range hints {
+		out[i] = h.String()
+	}
+
+	return strings.Join(out, ",")
+}
+
+// randomNumber is the number of HintRandomNumber, drawn on first use.
+type randomNumber struct {
+	once sync.Once
+	cfg  *Config
+	n    int
+}
+
+func (r *randomNumber) get() int {
+	r.once.Do(func() {
+		r.n = r.cfg.intn(99) + 1
+	})
+	return r.n
+}
+
+type randomNumberKey struct{}
+
+// contextWithRandomNumber adds the number of HintRandomNumber for one Generate call to
+// the context.
+func contextWithRandomNumber(ctx context.Context, cfg *Config) context.Context {
+	return context.WithValue(ctx, randomNumberKey{}, &randomNumber{cfg: cfg})
+}
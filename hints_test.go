@@ -0,0 +1,96 @@
+package sinoname
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHints(t *testing.T) {
+	hints := UserHints{
+		FirstName: "Patrick",
+		LastName:  "Arvatu",
+		Birthday:  time.Date(1998, time.April, 23, 0, 0, 0, 0, time.UTC),
+		Locale:    "en-US",
+		Interests: []string{"chess", "hiking"},
+		Email:     "lambels@example.com",
+	}
+	ctx := ContextWithHints(context.Background(), hints)
+
+	t.Run("Fallback_Hint", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := NumbersSuffix("", HintBirthYear, HintShortBirthYear)(&Config{MaxBytes: 100, Source: src, StripNumbers: stripNumbersASCII})
+
+		transformSequenceContext(t, ctx, tr, src, "lambels", "lambels1998", "lambels98", "lambels")
+	})
+
+	t.Run("Interests", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Suffix("_", HintInterests)(&Config{MaxBytes: 100, Source: src})
+
+		transformSequenceContext(t, ctx, tr, src, "lambels", "lambels_chess", "lambels_hiking")
+	})
+
+	t.Run("Random_Number", func(t *testing.T) {
+		tr, _ := NumbersSuffix("", HintRandomNumber)(testConfig)
+		out, err := tr.Transform(context.Background(), MessagePacket{Message: "lambels"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		n, err := strconv.Atoi(out.Message[len("lambels"):])
+		if err != nil || n < 1 || n > 99 {
+			t.Fatalf("expected a number between 1 and 99 got %v", out.Message)
+		}
+	})
+
+	t.Run("Random_Number_RandSrc", func(t *testing.T) {
+		draw := func() string {
+			cfg := &Config{MaxBytes: 100, Source: noopSource{true}, RandSrc: rand.New(rand.NewSource(1))}
+			tr, _ := NumbersSuffix("", HintRandomNumber)(cfg)
+			out, err := tr.Transform(context.Background(), MessagePacket{Message: "lambels"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return out.Message
+		}
+
+		if a, b := draw(), draw(); a != b {
+			t.Fatalf("expected the same number from the same seed got %v and %v", a, b)
+		}
+	})
+
+	t.Run("Random_Number_Per_Call", func(t *testing.T) {
+		gen := New(&Config{
+			MaxBytes:       100,
+			MaxVals:        10,
+			MaxChanges:     10,
+			PreventDefault: true,
+			Source:         noopSource{true},
+		}).WithTransformers(
+			NumbersSuffix("_", HintRandomNumber),
+			NumbersPrefix("_", HintRandomNumber),
+		)
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(vals)
+		if len(vals) != 2 || strings.TrimSuffix(vals[0], "_foo") != strings.TrimPrefix(vals[1], "foo_") {
+			t.Fatalf("expected the same number in both values got %v", vals)
+		}
+	})
+
+	t.Run("Describe", func(t *testing.T) {
+		tr, _ := Suffix("_", HintFirstName, HintInterests)(testConfig)
+		want := `Suffix(sep="_", hints=FirstName,Interests)`
+		if got := describeTransformer(tr).String(); got != want {
+			t.Fatalf("expected %v got %v", want, got)
+		}
+	})
+}
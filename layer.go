@@ -2,7 +2,6 @@ package sinoname
 
 import (
 	"context"
-	"errors"
 
 	"golang.org/x/sync/errgroup"
 )
@@ -43,13 +42,56 @@ type Layer interface {
 type LayerFactory func(cfg *Config) Layer
 
 // Layers is an abstraction type for multiple layers.
+//
+// Layers implements Layer by running its layers one after the other, this way a whole
+// chain of layers can be used as one layer in another pipeline (see Pipeline).
 type Layers []Layer
 
+// Pipeline returns a LayerFactory which chains the provided layers into one layer.
+//
+// Reusable sub-pipelines can be built once and composed:
+//
+//	casing := sinoname.Pipeline(
+//		sinoname.Transformers(sinoname.CamelCase, sinoname.SnakeCase),
+//		sinoname.Transformers(sinoname.Title, sinoname.Noop),
+//	)
+//
+// Each layer of the sub-pipeline counts as a layer when skipping layers via the Skip field.
+var Pipeline = func(lFact ...LayerFactory) LayerFactory {
+	return func(cfg *Config) Layer {
+		layers := make(Layers, len(lFact))
+		for i, f := range lFact {
+			layers[i] = f(cfg)
+		}
+
+		return layers
+	}
+}
+
+// PumpOut pumps the messages through all the layers in order, the out channel of the
+// last layer is returned.
+func (s Layers) PumpOut(ctx context.Context, g *errgroup.Group, in <-chan MessagePacket) (<-chan MessagePacket, error) {
+	if len(s) == 0 {
+		return nil, ErrNoLayers
+	}
+
+	var err error
+	lastOutC := in
+	for i, layer := range s {
+		lastOutC, err = layer.PumpOut(contextWithLayer(ctx, i), g, lastOutC)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return lastOutC, nil
+}
+
 // Run runs all the layers it owns returning a channel to read from and a cleanup function
 // which must be called in order to free all resources.
 func (s Layers) Run(ctx context.Context, in MessagePacket) (<-chan MessagePacket, func() error, error) {
 	if len(s) == 0 {
-		return nil, func() error { return nil }, errors.New("sinoname: generator has no layers")
+		return nil, func() error { return nil }, ErrNoLayers
 	}
 
 	// fanOutC is used to fanOut messages to the first layer.
@@ -74,22 +116,28 @@ func (s Layers) Run(ctx context.Context, in MessagePacket) (<-chan MessagePacket
 		return g.Wait()
 	}
 
+	// pooled generators run the layer go-routines on their worker pool.
+	if pool := poolFromContext(ctx); pool != nil {
+		pg := newPooledGroup(pool, ctx, cancel)
+		ctx = contextWithGroup(ctx, pg)
+		clnUp = func() error {
+			cancel()
+			pErr := pg.Wait()
+			if err := g.Wait(); pErr == nil {
+				return err
+			}
+			return pErr
+		}
+	}
+
 	// fanInC is used to fanIn all the messages from the last layer for the generator to consume.
 	//
 	// it is closed when either the context is cancelled by an error or explicit cancelation by the client
 	// or generator.
-	var fanInC <-chan MessagePacket
-	// start layers pipeline.
-	var err error
-	var lastOutC <-chan MessagePacket
-	lastOutC = fanOutC
-	for _, layer := range s {
-		lastOutC, err = layer.PumpOut(ctx, g, lastOutC)
-		if err != nil {
-			return nil, clnUp, err
-		}
+	fanInC, err := s.PumpOut(ctx, g, fanOutC)
+	if err != nil {
+		return nil, clnUp, err
 	}
-	fanInC = lastOutC
 
 	return fanInC, clnUp, nil
 }
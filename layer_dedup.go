@@ -0,0 +1,47 @@
+package sinoname
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DedupLayer drops the messages which were already seen by the layer in the current
+// Generate call, the first occurrence of each message passes through unchanged.
+//
+// Placing a DedupLayer between transformer layers stops the downstream layers from
+// transforming (and validating) the same message multiple times.
+//
+// The layer doesent count as a layer when skipping layers via the Skip field.
+type DedupLayer struct {
+	cfg *Config
+}
+
+// Dedup creates a DedupLayer.
+var Dedup = func(cfg *Config) Layer {
+	return &DedupLayer{
+		cfg: cfg,
+	}
+}
+
+func (l *DedupLayer) PumpOut(ctx context.Context, g *errgroup.Group, in <-chan MessagePacket) (<-chan MessagePacket, error) {
+	// the seen values are scoped to this PumpOut call (one Generate call) and only touched
+	// by the pumping go-routine.
+	seen := make(map[string]struct{})
+	keep := func(v MessagePacket) bool {
+		if _, ok := seen[v.Message]; ok {
+			return false
+		}
+
+		seen[v.Message] = struct{}{}
+		return true
+	}
+
+	return pumpFiltered(ctx, g, l.cfg, in, keep), nil
+}
+
+func (l *DedupLayer) Describe() LayerDescription {
+	return LayerDescription{
+		Type: "DedupLayer",
+	}
+}
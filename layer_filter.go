@@ -0,0 +1,90 @@
+package sinoname
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// FilterLayer drops the messages which dont satisfy its predicate, the rest of the
+// messages pass through the layer unchanged.
+//
+// The layer doesent count as a layer when skipping layers via the Skip field, dropping
+// messages early saves the source calls of all the downstream layers.
+type FilterLayer struct {
+	cfg  *Config
+	keep func(MessagePacket) bool
+}
+
+// Filter returns a LayerFactory which creates a FilterLayer keeping only the messages for
+// which keep returns true.
+//
+//	gen.WithLayers(sinoname.Filter(func(v sinoname.MessagePacket) bool {
+//		return len(v.Message) >= 4
+//	}))
+var Filter = func(keep func(MessagePacket) bool) LayerFactory {
+	return func(cfg *Config) Layer {
+		return &FilterLayer{
+			cfg:  cfg,
+			keep: keep,
+		}
+	}
+}
+
+func (l *FilterLayer) PumpOut(ctx context.Context, g *errgroup.Group, in <-chan MessagePacket) (<-chan MessagePacket, error) {
+	return pumpFiltered(ctx, g, l.cfg, in, l.keep), nil
+}
+
+func (l *FilterLayer) Describe() LayerDescription {
+	return LayerDescription{
+		Type: "FilterLayer",
+	}
+}
+
+// pumpFiltered sends out all the messages from in for which keep returns true. The out
+// channel is closed once in is closed or the context is cancelled.
+//
+// If keep panics the message is handled according to the PanicPolicy config field.
+func pumpFiltered(ctx context.Context, g *errgroup.Group, cfg *Config, in <-chan MessagePacket, keep func(MessagePacket) bool) <-chan MessagePacket {
+	outC := make(chan MessagePacket)
+	g.Go(func() error {
+		defer close(outC)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+
+				ok, err := safeKeep(ctx, keep, v)
+				if err != nil {
+					if err = applyPanicPolicy(cfg, err); err == ErrSkip {
+						continue
+					}
+					return err
+				}
+				if !ok {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case outC <- v:
+				}
+			}
+		}
+	})
+
+	return outC
+}
+
+// safeKeep runs keep recovering any panics.
+func safeKeep(ctx context.Context, keep func(MessagePacket) bool, v MessagePacket) (ok bool, err error) {
+	defer recoverPanic(ctx, &err)
+	return keep(v), nil
+}
@@ -0,0 +1,111 @@
+package sinoname
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+type countSource struct {
+	n int32
+}
+
+func (s *countSource) Valid(context.Context, string) (bool, error) {
+	atomic.AddInt32(&s.n, 1)
+	return true, nil
+}
+
+type validAddTransformer struct {
+	cfg *Config
+	add string
+}
+
+func (t validAddTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	out := in.Message + t.add
+	ok, err := t.cfg.Source.Valid(ctx, out)
+	if err != nil || !ok {
+		return in, err
+	}
+
+	in.setAndIncrement(out)
+	return in, nil
+}
+
+func newValidAddTransformer(add string) TransformerFactory {
+	return func(cfg *Config) (Transformer, bool) {
+		return validAddTransformer{cfg, add}, false
+	}
+}
+
+func TestFilter(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	gen := New(&Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    testConfig.MaxVals,
+		MaxChanges: 10,
+		Source:     noopSource{true},
+	}).WithTransformers(
+		newAddTransformer("a"),
+		newAddTransformer("bb"),
+		newAddTransformer("ccc"),
+	).WithLayers(Filter(func(v MessagePacket) bool {
+		return len(v.Message) > 4
+	})).WithTransformers(
+		newAddTransformer("_"),
+	)
+
+	vals, err := gen.Generate(context.Background(), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"foobb_", "fooccc_"}
+	sort.Strings(vals)
+	if strings.Join(vals, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected %v but got %v", want, vals)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	src := &countSource{}
+	gen := New(&Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    testConfig.MaxVals,
+		MaxChanges: 10,
+		Source:     src,
+	}).WithTransformers(
+		Noop,
+		Noop,
+		Noop,
+	).WithLayers(
+		Dedup,
+	).WithTransformers(
+		newValidAddTransformer("a"),
+		newValidAddTransformer("b"),
+	)
+
+	// run twice to make sure the seen values dont leak between Generate calls.
+	for i := 0; i < 2; i++ {
+		atomic.StoreInt32(&src.n, 0)
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"fooa", "foob"}
+		sort.Strings(vals)
+		if strings.Join(vals, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected %v but got %v", want, vals)
+		}
+		if n := atomic.LoadInt32(&src.n); n != 2 {
+			t.Fatalf("expected 2 source calls but got %v", n)
+		}
+	}
+}
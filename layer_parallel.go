@@ -0,0 +1,228 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Branch represents one branch of a ParallelLayer.
+type Branch struct {
+	// Layer is the layer (or sub-pipeline via Pipeline) ran by the branch.
+	Layer LayerFactory
+
+	// MaxChanges, when greater then 0, makes the branch ignore messages which went through
+	// more then MaxChanges changes.
+	MaxChanges int
+
+	// MaxVals, when greater then 0, limits the number of messages the branch sends out.
+	// Once the limit is reached the branch is stopped without affecting the other branches.
+	MaxVals int
+}
+
+// ParallelLayer runs its branches side by side, each message is sent to every branch
+// and the outputs of all the branches are merged back into one channel.
+//
+// Messages which skip the layer (via the Skip field or MaxChanges) arent sent to any
+// branch and pass through the layer unchanged.
+type ParallelLayer struct {
+	cfg      *Config
+	branches []branch
+}
+
+type branch struct {
+	layer      Layer
+	maxChanges int
+	maxVals    int
+}
+
+// Parallel returns a LayerFactory which creates a ParallelLayer with the provided branches.
+//
+//	gen.WithLayers(sinoname.Parallel(
+//		sinoname.Branch{Layer: casing},
+//		sinoname.Branch{Layer: numeric, MaxVals: 5},
+//	))
+var Parallel = func(branches ...Branch) LayerFactory {
+	return func(cfg *Config) Layer {
+		l := &ParallelLayer{
+			cfg:      cfg,
+			branches: make([]branch, len(branches)),
+		}
+
+		for i, b := range branches {
+			l.branches[i] = branch{
+				layer:      b.Layer(cfg),
+				maxChanges: b.MaxChanges,
+				maxVals:    b.MaxVals,
+			}
+		}
+
+		return l
+	}
+}
+
+func (l *ParallelLayer) PumpOut(ctx context.Context, g *errgroup.Group, in <-chan MessagePacket) (<-chan MessagePacket, error) {
+	if len(l.branches) == 0 {
+		return nil, ErrNoBranches
+	}
+
+	branchesC := make([]chan MessagePacket, len(l.branches))
+	stoppedC := make([]<-chan struct{}, len(l.branches))
+	outs := make([]<-chan MessagePacket, 0, len(l.branches)+1)
+	for i, b := range l.branches {
+		branchesC[i] = make(chan MessagePacket)
+
+		out, stopped, err := l.runBranch(contextWithLayer(ctx, i), g, b, branchesC[i])
+		if err != nil {
+			return nil, err
+		}
+		stoppedC[i] = stopped
+		outs = append(outs, out)
+	}
+	passC := make(chan MessagePacket)
+	outs = append(outs, passC)
+
+	g.Go(func() error {
+		defer func() {
+			for _, ch := range branchesC {
+				close(ch)
+			}
+			close(passC)
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+
+				if v.Changes > l.cfg.MaxChanges || v.Skip > 0 {
+					if v.Skip > 0 {
+						v.Skip--
+					}
+
+					select {
+					case <-ctx.Done():
+						return nil
+					case passC <- v:
+					}
+					continue
+				}
+
+				for i, b := range l.branches {
+					if b.maxChanges > 0 && v.Changes > b.maxChanges {
+						continue
+					}
+
+					select {
+					case <-ctx.Done():
+						return nil
+					case <-stoppedC[i]: // branch reached its MaxVals.
+					case branchesC[i] <- v:
+					}
+				}
+			}
+		}
+	})
+
+	return fanIn(ctx, g, outs...), nil
+}
+
+func (l *ParallelLayer) Describe() LayerDescription {
+	d := LayerDescription{
+		Type: "ParallelLayer",
+	}
+	for i, b := range l.branches {
+		sub := describeLayer(b.layer)
+		sub.Label = "branch " + strconv.Itoa(i)
+		if b.maxChanges > 0 {
+			sub.Params = append(sub.Params, Param{"MaxChanges", strconv.Itoa(b.maxChanges)})
+		}
+		if b.maxVals > 0 {
+			sub.Params = append(sub.Params, Param{"MaxVals", strconv.Itoa(b.maxVals)})
+		}
+		d.Layers = append(d.Layers, sub)
+	}
+
+	return d
+}
+
+// runBranch starts the branch layer.
+//
+// Branches without MaxVals run directly in g. Branches with MaxVals run in their own
+// errgroup so that they can be cancelled once they reach MaxVals without cancelling the
+// whole pipeline, the returned stopped channel is closed when this happens.
+func (l *ParallelLayer) runBranch(ctx context.Context, g *errgroup.Group, b branch, in <-chan MessagePacket) (<-chan MessagePacket, <-chan struct{}, error) {
+	if b.maxVals <= 0 {
+		out, err := b.layer.PumpOut(ctx, g, in)
+		return out, nil, err
+	}
+
+	bCtx, cancel := context.WithCancel(ctx)
+	bg, bCtx := errgroup.WithContext(bCtx)
+	out, err := b.layer.PumpOut(bCtx, bg, in)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	outC := make(chan MessagePacket)
+	stoppedC := make(chan struct{})
+	g.Go(func() error {
+		defer close(outC)
+
+		var sent int
+		var stopped bool
+		stop := func() {
+			if !stopped {
+				stopped = true
+				close(stoppedC)
+				cancel()
+			}
+		}
+
+	L:
+		for {
+			select {
+			case <-ctx.Done():
+				break L
+
+			case v, ok := <-out:
+				if !ok {
+					break L
+				}
+
+				select {
+				case <-ctx.Done():
+					break L
+				case outC <- v:
+				}
+
+				sent++
+				if sent == b.maxVals {
+					stop()
+					break L
+				}
+			}
+		}
+
+		// the branch may have exited by itself, mark it as stopped anyway to release
+		// the context.
+		wasStopped := stopped
+		stop()
+		err := bg.Wait()
+		if wasStopped && errors.Is(err, context.Canceled) && ctx.Err() == nil {
+			return nil
+		}
+
+		return err
+	})
+
+	return outC, stoppedC, nil
+}
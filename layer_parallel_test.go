@@ -0,0 +1,111 @@
+package sinoname
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestParallel(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	cfg := &Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    testConfig.MaxVals,
+		MaxChanges: 10,
+		Source:     noopSource{true},
+	}
+
+	t.Run("Merge_Branches", func(t *testing.T) {
+		casing := Pipeline(
+			Transformers(newAddTransformer("a"), newAddTransformer("b")),
+			Transformers(newAddTransformer("c")),
+		)
+		numeric := Transformers(newAddTransformer("1"))
+
+		gen := New(cfg).WithLayers(Parallel(
+			Branch{Layer: casing},
+			Branch{Layer: numeric},
+		))
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"foo1", "fooac", "foobc"}
+		sort.Strings(vals)
+		if strings.Join(vals, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected %v but got %v", want, vals)
+		}
+	})
+
+	t.Run("Branch_Max_Vals", func(t *testing.T) {
+		wide := Transformers(
+			newAddTransformer("a"),
+			newAddTransformer("b"),
+			newAddTransformer("c"),
+			newAddTransformer("d"),
+		)
+
+		gen := New(cfg).WithLayers(Parallel(
+			Branch{Layer: wide, MaxVals: 2},
+			Branch{Layer: Transformers(newAddTransformer("1"))},
+		))
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var n int
+		for _, v := range vals {
+			if v != "foo1" {
+				n++
+			}
+		}
+		if n != 2 || len(vals) != 3 {
+			t.Fatalf("expected 2 values from the limited branch and 1 from the other but got %v", vals)
+		}
+	})
+
+	t.Run("Branch_Max_Changes", func(t *testing.T) {
+		gen := New(cfg).WithTransformers(
+			newAddTransformer("a"),
+		).WithTransformers(
+			newAddTransformer("a"),
+		).WithLayers(Parallel(
+			Branch{Layer: Transformers(newAddTransformer("b")), MaxChanges: 2},
+			Branch{Layer: Transformers(newAddTransformer("c")), MaxChanges: 1},
+		))
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != 1 || vals[0] != "fooaab" {
+			t.Fatalf("expected [fooaab] but got %v", vals)
+		}
+	})
+
+	t.Run("Nested_Pipeline", func(t *testing.T) {
+		gen := New(cfg).WithLayers(
+			Pipeline(
+				Transformers(newAddTransformer("a")),
+				Pipeline(Transformers(newAddTransformer("b"))),
+			),
+			Transformers(newAddTransformer("c")),
+		)
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != 1 || vals[0] != "fooabc" {
+			t.Fatalf("expected [fooabc] but got %v", vals)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package sinoname
+
+import (
+	"context"
+	"strconv"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Route pairs a predicate with the layer which handles the messages matched by the
+// predicate.
+type Route struct {
+	// Match reports wether the message should be handled by the route. The context
+	// passed is the pipeline context, it carries the values added by the client (for
+	// example via ContextWithNumber).
+	Match func(ctx context.Context, v MessagePacket) bool
+	// Layer handles all the matched messages.
+	Layer LayerFactory
+	// Name optionally names the route in the pipeline description.
+	Name string
+}
+
+// When creates a route which passes the messages matched by match to a TransformerLayer
+// holding the provided transformers.
+func When(match func(ctx context.Context, v MessagePacket) bool, tFact ...TransformerFactory) Route {
+	return Route{
+		Match: match,
+		Layer: Transformers(tFact...),
+	}
+}
+
+// RouterLayer routes each message to the first route which matches it, if no route
+// matches the message it is passed to the default layer. The outputs of all the routes
+// are merged back into one channel.
+//
+// Messages which skip the layer (via the Skip field or MaxChanges) arent matched and
+// pass through the router unchanged.
+//
+// 1 message to a router results in as many messages as its matching route produces.
+type RouterLayer struct {
+	cfg    *Config
+	routes []route
+	def    Layer
+}
+
+type route struct {
+	match func(context.Context, MessagePacket) bool
+	layer Layer
+	name  string
+}
+
+// Router returns a LayerFactory which creates a RouterLayer with the provided routes.
+//
+// The routes are matched in order. If def is nil the messages which dont match any route
+// pass through the layer unchanged.
+//
+//	gen.WithLayers(sinoname.Router(
+//		sinoname.Transformers(sinoname.Prefix(""), sinoname.Suffix("")),
+//		sinoname.When(hasDigits, sinoname.NumbersSuffix("")),
+//		sinoname.When(hasManyTokens, sinoname.AbreviationPrefix("", false)),
+//	))
+var Router = func(def LayerFactory, routes ...Route) LayerFactory {
+	return func(cfg *Config) Layer {
+		l := &RouterLayer{
+			cfg:    cfg,
+			routes: make([]route, len(routes)),
+		}
+
+		for i, r := range routes {
+			l.routes[i] = route{
+				match: r.Match,
+				layer: r.Layer(cfg),
+				name:  r.Name,
+			}
+		}
+		if def != nil {
+			l.def = def(cfg)
+		}
+
+		return l
+	}
+}
+
+func (l *RouterLayer) PumpOut(ctx context.Context, g *errgroup.Group, in <-chan MessagePacket) (<-chan MessagePacket, error) {
+	// one channel per route, the default route and the pass through route.
+	routesC := make([]chan MessagePacket, len(l.routes)+2)
+	outs := make([]<-chan MessagePacket, 0, len(routesC))
+	for i := range routesC {
+		routesC[i] = make(chan MessagePacket)
+	}
+
+	defC := routesC[len(l.routes)]
+	passC := routesC[len(l.routes)+1]
+	for i, r := range l.routes {
+		out, err := r.layer.PumpOut(contextWithLayer(ctx, i), g, routesC[i])
+		if err != nil {
+			return nil, err
+		}
+		outs = append(outs, out)
+	}
+	if l.def != nil {
+		out, err := l.def.PumpOut(contextWithLayer(ctx, len(l.routes)), g, defC)
+		if err != nil {
+			return nil, err
+		}
+		outs = append(outs, out)
+	} else {
+		outs = append(outs, defC)
+	}
+	outs = append(outs, passC)
+
+	g.Go(func() error {
+		defer func() {
+			for _, ch := range routesC {
+				close(ch)
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+
+			case v, ok := <-in:
+				if !ok {
+					return nil
+				}
+
+				ch, err := l.dispatch(ctx, &v, routesC)
+				if err != nil {
+					if err = applyPanicPolicy(l.cfg, err); err == ErrSkip {
+						continue
+					}
+					return err
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case ch <- v:
+				}
+			}
+		}
+	})
+
+	return fanIn(ctx, g, outs...), nil
+}
+
+func (l *RouterLayer) Describe() LayerDescription {
+	d := LayerDescription{
+		Type: "RouterLayer",
+	}
+	for i, r := range l.routes {
+		sub := describeLayer(r.layer)
+		sub.Label = r.name
+		if sub.Label == "" {
+			sub.Label = "route " + strconv.Itoa(i)
+		}
+		d.Layers = append(d.Layers, sub)
+	}
+	if l.def != nil {
+		sub := describeLayer(l.def)
+		sub.Label = "default"
+		d.Layers = append(d.Layers, sub)
+	}
+
+	return d
+}
+
+// dispatch picks the channel which the message should be sent to.
+func (l *RouterLayer) dispatch(ctx context.Context, v *MessagePacket, routesC []chan MessagePacket) (chan MessagePacket, error) {
+	passC := routesC[len(routesC)-1]
+	if v.Changes > l.cfg.MaxChanges {
+		return passC, nil
+	}
+	if v.Skip > 0 {
+		v.Skip--
+		return passC, nil
+	}
+
+	for i, r := range l.routes {
+		ok, err := r.safeMatch(ctx, *v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return routesC[i], nil
+		}
+	}
+
+	return routesC[len(l.routes)], nil
+}
+
+// safeMatch runs the predicate of the route recovering any panics.
+func (r route) safeMatch(ctx context.Context, v MessagePacket) (ok bool, err error) {
+	defer recoverPanic(ctx, &err)
+	return r.match(ctx, v), nil
+}
@@ -0,0 +1,92 @@
+package sinoname
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestRouter(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	hasDigits := func(_ context.Context, v MessagePacket) bool {
+		return strings.ContainsAny(v.Message, "0123456789")
+	}
+	isShort := func(_ context.Context, v MessagePacket) bool {
+		return len(v.Message) < 5
+	}
+
+	cfg := &Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    testConfig.MaxVals,
+		MaxChanges: 10,
+		Source:     noopSource{true},
+	}
+
+	t.Run("Routes", func(t *testing.T) {
+		gen := New(cfg).WithTransformers(
+			newAddTransformer("1"),
+			newAddTransformer("a"),
+			newAddTransformer("long"),
+		).WithLayers(Router(
+			Transformers(newAddTransformer("_default")),
+			When(hasDigits, newAddTransformer("_digits")),
+			When(isShort, newAddTransformer("_short"), newAddTransformer("_short2")),
+		))
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"foo1_digits", "fooa_short", "fooa_short2", "foolong_default"}
+		sort.Strings(vals)
+		if strings.Join(vals, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected %v but got %v", want, vals)
+		}
+	})
+
+	t.Run("Nil_Default", func(t *testing.T) {
+		gen := New(cfg).WithTransformers(
+			newAddTransformer("1"),
+			newAddTransformer("long"),
+		).WithLayers(Router(
+			nil,
+			When(hasDigits, newAddTransformer("_digits")),
+		))
+
+		vals, err := gen.Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"foo1_digits", "foolong"}
+		sort.Strings(vals)
+		if strings.Join(vals, ",") != strings.Join(want, ",") {
+			t.Fatalf("expected %v but got %v", want, vals)
+		}
+	})
+
+	t.Run("Context_Values", func(t *testing.T) {
+		hasNumber := func(ctx context.Context, _ MessagePacket) bool {
+			_, ok := NumberFromContext(ctx)
+			return ok
+		}
+
+		gen := New(cfg).WithLayers(Router(
+			Transformers(newAddTransformer("_default")),
+			When(hasNumber, newAddTransformer("_number")),
+		))
+
+		vals, err := gen.Generate(ContextWithNumber(context.Background(), 1), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != 1 || vals[0] != "foo_number" {
+			t.Fatalf("expected [foo_number] but got %v", vals)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package sinoname
 
 import (
 	"context"
-	"errors"
 	"sync"
 	"sync/atomic"
 
@@ -19,11 +18,38 @@ type TransformerLayer struct {
 	init                 int32
 	transformers         []Transformer
 	transformerFactories []TransformerFactory
+	// descriptions of the transformers in the order they were provided.
+	descriptions []TransformerDescription
+}
+
+// Transformers returns a LayerFactory which groups the provided transformers in a
+// TransformerLayer.
+func Transformers(tFact ...TransformerFactory) LayerFactory {
+	return func(cfg *Config) Layer {
+		tLayer := &TransformerLayer{
+			cfg:                  cfg,
+			transformers:         make([]Transformer, len(tFact)),
+			transformerFactories: make([]TransformerFactory, 0),
+			descriptions:         make([]TransformerDescription, len(tFact)),
+		}
+
+		for i, f := range tFact {
+			t, statefull := f(cfg)
+			if statefull {
+				tLayer.transformerFactories = append(tLayer.transformerFactories, f)
+			}
+			tLayer.transformers[i] = t
+			tLayer.descriptions[i] = describeTransformer(t)
+			tLayer.descriptions[i].Statefull = statefull
+		}
+
+		return tLayer
+	}
 }
 
 func (l *TransformerLayer) PumpOut(ctx context.Context, g *errgroup.Group, in <-chan MessagePacket) (<-chan MessagePacket, error) {
 	if len(l.transformers) == 0 && len(l.transformerFactories) == 0 {
-		return nil, errors.New("sinoname: layer has no transformers")
+		return nil, ErrNoTransformers
 	}
 
 	// local copy of statefull trasnformers.
@@ -74,6 +100,13 @@ func (l *TransformerLayer) PumpOut(ctx context.Context, g *errgroup.Group, in <-
 	return outC, nil
 }
 
+func (l *TransformerLayer) Describe() LayerDescription {
+	return LayerDescription{
+		Type:         "TransformerLayer",
+		Transformers: append([]TransformerDescription(nil), l.descriptions...),
+	}
+}
+
 func (l *TransformerLayer) getStatefullTransformers() []Transformer {
 	if len(l.transformerFactories) == 0 {
 		return nil
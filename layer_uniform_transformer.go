@@ -2,7 +2,6 @@ package sinoname
 
 import (
 	"context"
-	"errors"
 	"sync"
 	"sync/atomic"
 
@@ -27,11 +26,38 @@ type UniformTransformerLayer struct {
 	init                 int32
 	transformers         []Transformer
 	transformerFactories []TransformerFactory
+	// descriptions of the transformers in the order they were provided.
+	descriptions []TransformerDescription
+}
+
+// UniformTransformers returns a LayerFactory which groups the provided transformers in a
+// UniformTransformerLayer.
+func UniformTransformers(tFact ...TransformerFactory) LayerFactory {
+	return func(cfg *Config) Layer {
+		uLayer := &UniformTransformerLayer{
+			cfg:                  cfg,
+			transformers:         make([]Transformer, len(tFact)),
+			transformerFactories: make([]TransformerFactory, 0),
+			descriptions:         make([]TransformerDescription, len(tFact)),
+		}
+
+		for i, f := range tFact {
+			t, statefull := f(cfg)
+			if statefull {
+				uLayer.transformerFactories = append(uLayer.transformerFactories, f)
+			}
+			uLayer.transformers[i] = t
+			uLayer.descriptions[i] = describeTransformer(t)
+			uLayer.descriptions[i].Statefull = statefull
+		}
+
+		return uLayer
+	}
 }
 
 func (l *UniformTransformerLayer) PumpOut(ctx context.Context, g *errgroup.Group, in <-chan MessagePacket) (<-chan MessagePacket, error) {
 	if len(l.transformers) == 0 && len(l.transformerFactories) == 0 {
-		return nil, errors.New("sinoname: layer has no transformers")
+		return nil, ErrNoTransformers
 	}
 
 	// local copy of statefull trasnformers.
@@ -65,7 +91,17 @@ func (l *UniformTransformerLayer) PumpOut(ctx context.Context, g *errgroup.Group
 			return
 		}
 
-		wg.Wait()
+		// the writers wait for each other in out, if the context is cancelled while
+		// waiting some writers may never come, close out to release the others.
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
 		out.Close()
 	}
 	broadcast := newPacketBroadcatser(
@@ -83,6 +119,13 @@ func (l *UniformTransformerLayer) PumpOut(ctx context.Context, g *errgroup.Group
 	return outC, nil
 }
 
+func (l *UniformTransformerLayer) Describe() LayerDescription {
+	return LayerDescription{
+		Type:         "UniformTransformerLayer",
+		Transformers: append([]TransformerDescription(nil), l.descriptions...),
+	}
+}
+
 func (l *UniformTransformerLayer) getStatefullTransformers() []Transformer {
 	if len(l.transformerFactories) == 0 {
 		return nil
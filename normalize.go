@@ -0,0 +1,281 @@
+package sinoname
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NormalizationForm is a unicode normalization form.
+type NormalizationForm int
+
+const (
+	// NoNormalization leaves the string as is.
+	NoNormalization NormalizationForm = iota
+	// NFC is the canonical decomposition followed by the canonical composition: the
+	// precomposed and decomposed forms of Renée both become the precomposed form.
+	NFC
+	// NFD is the canonical decomposition.
+	NFD
+	// NFKC is the compatibility decomposition followed by the canonical composition:
+	// ﬁ, ｆｉ and 𝐟𝐢 all become fi.
+	NFKC
+	// NFKD is the compatibility decomposition.
+	NFKD
+)
+
+func (f NormalizationForm) String() string {
+	switch f {
+	case NoNormalization:
+		return "None"
+	case NFC:
+		return "NFC"
+	case NFD:
+		return "NFD"
+	case NFKC:
+		return "NFKC"
+	case NFKD:
+		return "NFKD"
+	default:
+		return "NormalizationForm(" + strconv.Itoa(int(f)) + ")"
+	}
+}
+
+// Normalize normalizes the string to the provided form, if stripMarks is true the
+// combining marks (diacritics) are stripped: Zoë -> Zoe. Stripping the marks with
+// NoNormalization normalizes the string to NFC.
+//
+// The decompositions are limited to the tables of the package, see normalize_tables.go .
+var Normalize = func(form NormalizationForm, stripMarks bool) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		return &normalizeTransformer{
+			cfg:        cfg,
+			form:       form,
+			stripMarks: stripMarks,
+		}, false
+	}
+}
+
+type normalizeTransformer struct {
+	cfg        *Config
+	form       NormalizationForm
+	stripMarks bool
+}
+
+func (t *normalizeTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Normalize",
+		Params: []Param{
+			{"form", t.form.String()},
+			{"stripMarks", strconv.FormatBool(t.stripMarks)},
+		},
+	}
+}
+
+func (t *normalizeTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	out := normalize(in.Message, t.form, t.stripMarks)
+	if out == in.Message || out == "" || (t.cfg.MaxBytes > 0 && len(out) > t.cfg.MaxBytes) {
+		return in, nil
+	}
+
+	ok, err := t.cfg.Source.Valid(ctx, out)
+	if ok {
+		in.setAndIncrement(out)
+	}
+
+	return in, err
+}
+
+// normalize normalizes s to the form, stripping the combining marks if stripMarks is true.
+func normalize(s string, form NormalizationForm, stripMarks bool) string {
+	if form == NoNormalization {
+		if !stripMarks {
+			return s
+		}
+		form = NFC
+	}
+
+	// FASTPATH: ascii strings are normalized in all the forms.
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+
+	compat := form == NFKC || form == NFKD
+	rs := make([]rune, 0, len(s))
+	for _, r := range s {
+		rs = decompose(rs, r, compat)
+	}
+	reorder(rs)
+
+	if stripMarks {
+		kept := rs[:0]
+		for _, r := range rs {
+			if !unicode.Is(unicode.Mn, r) {
+				kept = append(kept, r)
+			}
+		}
+		rs = kept
+	}
+
+	if form == NFC || form == NFKC {
+		rs = compose(rs)
+	}
+
+	return string(rs)
+}
+
+// hangul syllable constants, see the unicode standard chapter 3.12 .
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+// decompose appends the full decomposition of r to dst.
+func decompose(dst []rune, r rune, compat bool) []rune {
+	if r < utf8.RuneSelf {
+		return append(dst, r)
+	}
+
+	if s := r - hangulSBase; s >= 0 && s < hangulSCount {
+		dst = append(dst, hangulLBase+s/hangulNCount, hangulVBase+(s%hangulNCount)/hangulTCount)
+		if t := s % hangulTCount; t != 0 {
+			dst = append(dst, hangulTBase+t)
+		}
+		return dst
+	}
+
+	d, ok := canonicalDecompositions[r]
+	if !ok && compat {
+		d, ok = compatibilityDecompositions[r]
+	}
+	if !ok {
+		return append(dst, r)
+	}
+
+	for _, v := range d {
+		dst = decompose(dst, v, compat)
+	}
+	return dst
+}
+
+// reorder sorts the runs of combining marks by their combining class (canonical ordering).
+func reorder(rs []rune) {
+	for i := 1; i < len(rs); i++ {
+		cc := combiningClass(rs[i])
+		if cc == 0 {
+			continue
+		}
+
+		for j := i; j > 0; j-- {
+			prev := combiningClass(rs[j-1])
+			if prev == 0 || prev <= cc {
+				break
+			}
+			rs[j-1], rs[j] = rs[j], rs[j-1]
+		}
+	}
+}
+
+// compose runs the canonical composition algorithm on the decomposed runes.
+func compose(rs []rune) []rune {
+	out := rs[:0]
+	starter := -1
+	var lastCC uint8
+	for _, r := range rs {
+		cc := combiningClass(r)
+		if starter >= 0 {
+			// r isnt blocked from the starter.
+			if len(out)-1 == starter || (lastCC != 0 && lastCC < cc) {
+				if c, ok := composePair(out[starter], r); ok {
+					out[starter] = c
+					continue
+				}
+			}
+		}
+
+		if cc == 0 {
+			starter = len(out)
+		}
+		lastCC = cc
+		out = append(out, r)
+	}
+
+	return out
+}
+
+// composePair returns the primary composite of a and b.
+func composePair(a, b rune) (rune, bool) {
+	// hangul LV.
+	if l, v := a-hangulLBase, b-hangulVBase; l >= 0 && l < hangulLCount && v >= 0 && v < hangulVCount {
+		return hangulSBase + (l*hangulVCount+v)*hangulTCount, true
+	}
+	// hangul LVT.
+	if s, t := a-hangulSBase, b-hangulTBase; s >= 0 && s < hangulSCount && s%hangulTCount == 0 && t > 0 && t < hangulTCount {
+		return a + t, true
+	}
+
+	c, ok := compositionTable()[[2]rune{a, b}]
+	return c, ok
+}
+
+var (
+	compositionsOnce sync.Once
+	compositions     map[[2]rune]rune
+)
+
+// compositionTable returns the primary composites of the canonical pairs, built on first
+// use.
+func compositionTable() map[[2]rune]rune {
+	compositionsOnce.Do(func() {
+		compositions = make(map[[2]rune]rune, len(canonicalDecompositions))
+		for r, d := range canonicalDecompositions {
+			if len(d) != 2 || combiningClass(r) != 0 || combiningClass(d[0]) != 0 {
+				continue
+			}
+			if _, ok := compositionExclusions[r]; ok {
+				continue
+			}
+
+			compositions[[2]rune{d[0], d[1]}] = r
+		}
+	})
+
+	return compositions
+}
+
+type combiningClassRange struct {
+	lo, hi rune
+	class  uint8
+}
+
+// combiningClass returns the canonical combining class of r.
+func combiningClass(r rune) uint8 {
+	if r < 0x0300 {
+		return 0
+	}
+
+	i := sort.Search(len(combiningClasses), func(i int) bool {
+		return combiningClasses[i].hi >= r
+	})
+	if i < len(combiningClasses) && combiningClasses[i].lo <= r {
+		return combiningClasses[i].class
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package sinoname
+
+import (
+	"context"
+	"sort"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	const (
+		composed   = "Renée"
+		decomposed = "Rene\u0301e"
+	)
+
+	for _, tc := range []struct {
+		name       string
+		form       NormalizationForm
+		stripMarks bool
+		in, out    string
+	}{
+		{"NFC", NFC, false, decomposed, composed},
+		{"NFD", NFD, false, composed, decomposed},
+		{"NFC_Ordering", NFC, false, "a\u0323\u0302", "ậ"},
+		{"NFD_Ordering", NFD, false, "â\u0323", "a\u0323\u0302"},
+		{"NFKC", NFKC, false, "ﬁｎ\U0001d41e", "fine"},
+		{"NFKD", NFKD, false, "①½", "11⁄2"},
+		{"Hangul_NFC", NFC, false, "\u1112\u1161\u11ab", "한"},
+		{"Hangul_NFD", NFD, false, "한", "\u1112\u1161\u11ab"},
+		{"Strip_Marks", NFC, true, "Zoë " + decomposed, "Zoe Renee"},
+		{"Strip_Marks_No_Form", NoNormalization, true, composed, "Renee"},
+		{"No_Normalization", NoNormalization, false, decomposed, decomposed},
+		{"Ascii", NFKC, true, "lambels", "lambels"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, _ := Normalize(tc.form, tc.stripMarks)(testConfig)
+
+			out, err := tr.Transform(context.Background(), MessagePacket{Message: tc.in})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Message != tc.out {
+				t.Fatalf("expected %+q got %+q", tc.out, out.Message)
+			}
+		})
+	}
+
+	t.Run("Max_Bytes", func(t *testing.T) {
+		tr, _ := Normalize(NFD, false)(&Config{MaxBytes: len(composed), Source: noopSource{true}})
+
+		out, err := tr.Transform(context.Background(), MessagePacket{Message: composed})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Message != composed {
+			t.Fatalf("expected %+q got %+q", composed, out.Message)
+		}
+	})
+
+	t.Run("Config", func(t *testing.T) {
+		gen := New(&Config{
+			MaxBytes:          testConfig.MaxBytes,
+			MaxVals:           testConfig.MaxVals,
+			Source:            noopSource{true},
+			PreventDuplicates: true,
+			Normalization:     NFC,
+		}).WithTransformers(
+			Noop,
+			SnakeCase,
+			Suffix("_", HintString),
+		)
+
+		ctx := ContextWithString(context.Background(), "x")
+		want, err := gen.Generate(ctx, composed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := gen.Generate(ctx, decomposed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Strings(want)
+		sort.Strings(got)
+		if len(got) != len(want) {
+			t.Fatalf("expected %+q got %+q", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %+q got %+q", want, got)
+			}
+		}
+	})
+}
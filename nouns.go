@@ -0,0 +1,162 @@
+package sinoname
+
+// AnimalNouns is a list of animal nouns used by the Compound transformer.
+var AnimalNouns = []string{
+	"badger",
+	"bear",
+	"beaver",
+	"bison",
+	"cat",
+	"cheetah",
+	"cobra",
+	"coyote",
+	"crane",
+	"crow",
+	"deer",
+	"dolphin",
+	"dragon",
+	"eagle",
+	"falcon",
+	"ferret",
+	"fox",
+	"frog",
+	"gecko",
+	"hawk",
+	"hedgehog",
+	"heron",
+	"jaguar",
+	"koala",
+	"lemur",
+	"leopard",
+	"lion",
+	"lynx",
+	"moose",
+	"narwhal",
+	"octopus",
+	"orca",
+	"otter",
+	"owl",
+	"panda",
+	"panther",
+	"parrot",
+	"penguin",
+	"puma",
+	"rabbit",
+	"raven",
+	"salmon",
+	"seal",
+	"shark",
+	"sparrow",
+	"squirrel",
+	"tiger",
+	"toucan",
+	"turtle",
+	"walrus",
+	"whale",
+	"wolf",
+	"wombat",
+}
+
+// ObjectNouns is a list of object nouns used by the Compound transformer.
+var ObjectNouns = []string{
+	"anchor",
+	"arrow",
+	"banjo",
+	"barrel",
+	"beacon",
+	"blade",
+	"bottle",
+	"button",
+	"candle",
+	"compass",
+	"crown",
+	"cube",
+	"engine",
+	"feather",
+	"flute",
+	"gadget",
+	"guitar",
+	"hammer",
+	"helmet",
+	"kettle",
+	"kite",
+	"lantern",
+	"lens",
+	"magnet",
+	"marble",
+	"mirror",
+	"needle",
+	"paddle",
+	"pencil",
+	"piano",
+	"pixel",
+	"rocket",
+	"saddle",
+	"scroll",
+	"shield",
+	"spoon",
+	"sprocket",
+	"teapot",
+	"telescope",
+	"toaster",
+	"trumpet",
+	"tuba",
+	"umbrella",
+	"violin",
+	"whistle",
+	"widget",
+}
+
+// NatureNouns is a list of nature nouns used by the Compound transformer.
+var NatureNouns = []string{
+	"aurora",
+	"avalanche",
+	"blizzard",
+	"breeze",
+	"brook",
+	"canyon",
+	"cliff",
+	"cloud",
+	"comet",
+	"coral",
+	"crater",
+	"desert",
+	"dune",
+	"ember",
+	"fern",
+	"forest",
+	"frost",
+	"geyser",
+	"glacier",
+	"grove",
+	"harbor",
+	"island",
+	"lagoon",
+	"leaf",
+	"meadow",
+	"meteor",
+	"moon",
+	"nebula",
+	"oasis",
+	"ocean",
+	"pebble",
+	"pine",
+	"rain",
+	"reef",
+	"river",
+	"sky",
+	"snow",
+	"spring",
+	"star",
+	"storm",
+	"stream",
+	"summit",
+	"sun",
+	"thunder",
+	"tide",
+	"valley",
+	"volcano",
+	"wave",
+	"willow",
+	"wind",
+}
@@ -0,0 +1,361 @@
+package sinoname
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Observer is notified about what happens in the pipeline, it is used to plug in metrics
+// and tracing (Prometheus, OpenTelemetry, ...) without the pipeline depending on them.
+//
+// The observer is set via the Observer config field before calling New. Its methods are
+// called concurrently from the pipeline go-routines and should return fast, embed
+// NopObserver to implement only the needed methods.
+type Observer interface {
+	// OnTransformStart is called before a transformer processes a message.
+	OnTransformStart(ctx context.Context, e TransformEvent)
+	// OnTransformEnd is called after a transformer processed a message.
+	OnTransformEnd(ctx context.Context, e TransformEvent)
+	// OnSourceCall is called after each call to the source.
+	OnSourceCall(ctx context.Context, e SourceEvent)
+	// OnSkip is called when a message is skipped by a transformer (via ErrSkip) or skips
+	// a whole layer (via the Skip field or MaxChanges).
+	OnSkip(ctx context.Context, e SkipEvent)
+	// OnTimeout is called when a transformer call times out.
+	OnTimeout(ctx context.Context, e TransformEvent)
+	// OnLayerExit is called when a transformer layer exits.
+	OnLayerExit(ctx context.Context, e LayerEvent)
+	// OnGenerateDone is called when a Generate (or GenerateDetailed) call returns.
+	OnGenerateDone(ctx context.Context, e GenerateEvent)
+}
+
+// TransformEvent describes a transformer call.
+type TransformEvent struct {
+	// Layer is the index of the top level layer in the pipeline running it.
+	Layer int
+	// LayerPath is the path of the layer from the top level layer, one index per level of
+	// nesting (sub-pipeline, parallel branch or route), it uniquely identifies the layer
+	// and must not be modified.
+	LayerPath []int
+	// Index is the index of the transformer in its layer.
+	Index int
+	// Transformer is the name of the transformer.
+	Transformer string
+	// In is the message passed to the transformer.
+	In MessagePacket
+
+	// Out is the message returned by the transformer, only set by OnTransformEnd.
+	Out MessagePacket
+	// Err is the error returned by the transformer, only set by OnTransformEnd.
+	Err error
+	// Duration is the time taken by the transformer, only set by OnTransformEnd and
+	// OnTimeout.
+	Duration time.Duration
+}
+
+// SourceEvent describes a call to the source.
+type SourceEvent struct {
+	// Layer is the index of the top level layer calling the source, -1 if unknown.
+	Layer int
+	// LayerPath is the path of the layer calling the source, nil if unknown. See
+	// TransformEvent.LayerPath .
+	LayerPath []int
+	// Index is the index of the transformer calling the source, -1 if unknown.
+	Index int
+	// Transformer is the name of the transformer calling the source, "" if unknown.
+	Transformer string
+	// Value is the validated value.
+	Value string
+	// Valid is the value returned by the source.
+	Valid bool
+	// Err is the error returned by the source.
+	Err error
+	// Duration is the time taken by the source.
+	Duration time.Duration
+}
+
+// SkipEvent describes a skipped message.
+type SkipEvent struct {
+	// Layer is the index of the top level layer in the pipeline running it.
+	Layer int
+	// LayerPath is the path of the layer, see TransformEvent.LayerPath .
+	LayerPath []int
+	// Index is the index of the skipping transformer, -1 if the message skipped the
+	// whole layer.
+	Index int
+	// Transformer is the name of the skipping transformer, "" if the message skipped the
+	// whole layer.
+	Transformer string
+	// Packet is the skipped message.
+	Packet MessagePacket
+}
+
+// LayerEvent describes a layer exit.
+type LayerEvent struct {
+	// Layer is the index of the top level layer in the pipeline running it.
+	Layer int
+	// LayerPath is the path of the layer, see TransformEvent.LayerPath .
+	LayerPath []int
+	// Values is the number of messages fanned out to the transformers of the layer.
+	Values int
+	// Cancelled indicates that the layer exited because the context was cancelled.
+	Cancelled bool
+}
+
+// GenerateEvent describes a finished Generate call.
+type GenerateEvent struct {
+	// Input is the value passed to Generate.
+	Input string
+	// Result is the result of the call.
+	Result Result
+	// Err is the error returned by the call.
+	Err error
+	// Duration is the time taken by the call.
+	Duration time.Duration
+}
+
+// NopObserver implements Observer by doing nothing, embed it to implement only some of
+// the Observer methods.
+type NopObserver struct{}
+
+func (NopObserver) OnTransformStart(context.Context, TransformEvent) {}
+func (NopObserver) OnTransformEnd(context.Context, TransformEvent)   {}
+func (NopObserver) OnSourceCall(context.Context, SourceEvent)        {}
+func (NopObserver) OnSkip(context.Context, SkipEvent)                {}
+func (NopObserver) OnTimeout(context.Context, TransformEvent)        {}
+func (NopObserver) OnLayerExit(context.Context, LayerEvent)          {}
+func (NopObserver) OnGenerateDone(context.Context, GenerateEvent)    {}
+
+// TransformerStats holds the stats of one transformer collected by StatsObserver.
+//
+// The stats are kept per transformer of each layer, the transformers of nested layers
+// (sub-pipelines, parallel branches and routes) are told apart by LayerPath.
+type TransformerStats struct {
+	Layer       int
+	LayerPath   []int
+	Index       int
+	Transformer string
+
+	// Calls is the number of messages processed by the transformer.
+	Calls int
+	// Changes is the number of messages changed by the transformer.
+	Changes int
+	// Errors is the number of calls which returned an error (other then ErrSkip).
+	Errors int
+	// Skips is the number of messages skipped by the transformer.
+	Skips int
+	// Timeouts is the number of calls which timed out.
+	Timeouts int
+	// SourceCalls is the number of values the transformer checked against the source.
+	SourceCalls int
+	// SourceValid is the number of values the source accepted.
+	SourceValid int
+	// Duration is the total time spent in the transformer.
+	Duration time.Duration
+}
+
+// LayerStats holds the stats of one layer collected by StatsObserver.
+type LayerStats struct {
+	Layer     int
+	LayerPath []int
+	// Values is the number of messages fanned out to the transformers of the layer.
+	Values int
+	// Skips is the number of messages which skipped the whole layer.
+	Skips int
+	// Exits is the number of times the layer exited.
+	Exits int
+	// Cancelled is the number of times the layer exited because of a context cancellation.
+	Cancelled int
+}
+
+// StatsObserver is an in-memory Observer collecting counters per transformer and layer,
+// it is safe for concurrent use.
+type StatsObserver struct {
+	mu           sync.Mutex
+	transformers map[transformerStatsKey]*TransformerStats
+	layers       map[string]*LayerStats
+	generates    int
+}
+
+var _ Observer = (*StatsObserver)(nil)
+
+// NewStatsObserver creates an empty StatsObserver.
+func NewStatsObserver() *StatsObserver {
+	return &StatsObserver{
+		transformers: make(map[transformerStatsKey]*TransformerStats),
+		layers:       make(map[string]*LayerStats),
+	}
+}
+
+// transformerStatsKey identifies a transformer by the path of its layer and its index.
+type transformerStatsKey struct {
+	path  string
+	index int
+}
+
+// pathKey encodes a layer path as a map key.
+func pathKey(path []int) string {
+	var sb strings.Builder
+	for i, p := range path {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(strconv.Itoa(p))
+	}
+	return sb.String()
+}
+
+// comparePaths compares two layer paths element by element, shorter paths first.
+func comparePaths(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
+// must be called with the lock held.
+func (s *StatsObserver) transformer(layer int, path []int, index int, name string) *TransformerStats {
+	key := transformerStatsKey{pathKey(path), index}
+	t, ok := s.transformers[key]
+	if !ok {
+		t = &TransformerStats{
+			Layer:       layer,
+			LayerPath:   append([]int(nil), path...),
+			Index:       index,
+			Transformer: name,
+		}
+		s.transformers[key] = t
+	}
+
+	return t
+}
+
+// must be called with the lock held.
+func (s *StatsObserver) layer(layer int, path []int) *LayerStats {
+	key := pathKey(path)
+	l, ok := s.layers[key]
+	if !ok {
+		l = &LayerStats{
+			Layer:     layer,
+			LayerPath: append([]int(nil), path...),
+		}
+		s.layers[key] = l
+	}
+
+	return l
+}
+
+func (s *StatsObserver) OnTransformStart(context.Context, TransformEvent) {}
+
+func (s *StatsObserver) OnTransformEnd(_ context.Context, e TransformEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.transformer(e.Layer, e.LayerPath, e.Index, e.Transformer)
+	t.Calls++
+	t.Duration += e.Duration
+	if e.Err != nil && e.Err != ErrSkip {
+		t.Errors++
+	}
+	if e.Err == nil && e.Out.Changes > e.In.Changes {
+		t.Changes++
+	}
+}
+
+func (s *StatsObserver) OnSourceCall(_ context.Context, e SourceEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.transformer(e.Layer, e.LayerPath, e.Index, e.Transformer)
+	t.SourceCalls++
+	if e.Valid && e.Err == nil {
+		t.SourceValid++
+	}
+}
+
+func (s *StatsObserver) OnSkip(_ context.Context, e SkipEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e.Index == -1 {
+		s.layer(e.Layer, e.LayerPath).Skips++
+		return
+	}
+	s.transformer(e.Layer, e.LayerPath, e.Index, e.Transformer).Skips++
+}
+
+func (s *StatsObserver) OnTimeout(_ context.Context, e TransformEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transformer(e.Layer, e.LayerPath, e.Index, e.Transformer).Timeouts++
+}
+
+func (s *StatsObserver) OnLayerExit(_ context.Context, e LayerEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := s.layer(e.Layer, e.LayerPath)
+	l.Values += e.Values
+	l.Exits++
+	if e.Cancelled {
+		l.Cancelled++
+	}
+}
+
+func (s *StatsObserver) OnGenerateDone(context.Context, GenerateEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generates++
+}
+
+// Transformers returns a copy of the transformer stats ordered by layer path and index.
+func (s *StatsObserver) Transformers() []TransformerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]TransformerStats, 0, len(s.transformers))
+	for _, t := range s.transformers {
+		out = append(out, *t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if c := comparePaths(out[i].LayerPath, out[j].LayerPath); c != 0 {
+			return c < 0
+		}
+		return out[i].Index < out[j].Index
+	})
+
+	return out
+}
+
+// Layers returns a copy of the layer stats ordered by layer path.
+func (s *StatsObserver) Layers() []LayerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]LayerStats, 0, len(s.layers))
+	for _, l := range s.layers {
+		out = append(out, *l)
+	}
+	sort.Slice(out, func(i, j int) bool { return comparePaths(out[i].LayerPath, out[j].LayerPath) < 0 })
+
+	return out
+}
+
+// Generates returns the number of finished Generate calls.
+func (s *StatsObserver) Generates() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.generates
+}
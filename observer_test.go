@@ -0,0 +1,166 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestStatsObserver(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	stats := NewStatsObserver()
+	gen := New(&Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    testConfig.MaxVals,
+		MaxChanges: 0,
+		Source:     newStaticSource("foos"),
+		Observer:   stats,
+	}).WithTransformers(
+		Plural,
+		newAddTransformer("a"),
+		newErrorTransformer(ErrSkip),
+	).WithTransformers(
+		Title,
+	)
+
+	if _, err := gen.Generate(context.Background(), "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if n := stats.Generates(); n != 1 {
+		t.Fatalf("expected 1 generate call but got %v", n)
+	}
+
+	tStats := stats.Transformers()
+	if len(tStats) != 4 {
+		t.Fatalf("expected stats for 4 transformers but got %+v", tStats)
+	}
+
+	plural := tStats[0]
+	if plural.Layer != 0 || plural.Transformer != "Plural" || plural.Calls != 1 || plural.Changes != 0 || plural.SourceCalls != 1 || plural.SourceValid != 0 {
+		t.Fatalf("unexpected plural stats: %+v", plural)
+	}
+	if add := tStats[1]; add.Calls != 1 || add.Changes != 1 || add.SourceCalls != 0 {
+		t.Fatalf("unexpected add stats: %+v", add)
+	}
+	if skip := tStats[2]; skip.Calls != 1 || skip.Skips != 1 || skip.Errors != 0 {
+		t.Fatalf("unexpected skip stats: %+v", skip)
+	}
+	// "foo" from plural is transformed, "fooa" went through 1 change and skips the layer.
+	if title := tStats[3]; title.Layer != 1 || title.Calls != 1 || title.SourceCalls != 1 || title.SourceValid != 1 {
+		t.Fatalf("unexpected title stats: %+v", title)
+	}
+
+	lStats := stats.Layers()
+	if len(lStats) != 2 {
+		t.Fatalf("expected stats for 2 layers but got %+v", lStats)
+	}
+	if l := lStats[0]; l.Values != 1 || l.Exits != 1 || l.Skips != 0 {
+		t.Fatalf("unexpected first layer stats: %+v", l)
+	}
+	if l := lStats[1]; l.Values != 1 || l.Exits != 1 || l.Skips != 1 {
+		t.Fatalf("unexpected second layer stats: %+v", l)
+	}
+}
+
+func TestStatsObserverNested(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	stats := NewStatsObserver()
+	gen := New(&Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    testConfig.MaxVals,
+		MaxChanges: 10,
+		Source:     noopSource{true},
+		Tokenize:   testConfig.Tokenize,
+		Observer:   stats,
+	}).WithLayers(Parallel(
+		Branch{Layer: Transformers(SnakeCase)},
+		Branch{Layer: Pipeline(Transformers(Noop), Transformers(KebabCase))},
+	))
+
+	if _, err := gen.Generate(context.Background(), "fooBar"); err != nil {
+		t.Fatal(err)
+	}
+
+	tStats := stats.Transformers()
+	if len(tStats) != 3 {
+		t.Fatalf("expected stats for 3 transformers but got %+v", tStats)
+	}
+	for i, want := range []struct {
+		path string
+		name string
+	}{
+		{"0.0", "SnakeCase"},
+		{"0.1.0", "Noop"},
+		{"0.1.1", "KebabCase"},
+	} {
+		if got := tStats[i]; pathKey(got.LayerPath) != want.path || got.Layer != 0 || got.Index != 0 || got.Transformer != want.name || got.Calls != 1 {
+			t.Fatalf("expected %v at %v got %+v", want.name, want.path, got)
+		}
+	}
+
+	if lStats := stats.Layers(); len(lStats) != 3 {
+		t.Fatalf("expected stats for 3 layers but got %+v", lStats)
+	}
+}
+
+// panicObserver panics in OnTransformEnd or OnLayerExit.
+type panicObserver struct {
+	NopObserver
+	onExit bool
+}
+
+func (o panicObserver) OnTransformEnd(context.Context, TransformEvent) {
+	if !o.onExit {
+		panic("observer panic")
+	}
+}
+
+func (o panicObserver) OnLayerExit(context.Context, LayerEvent) {
+	if o.onExit {
+		panic("observer panic")
+	}
+}
+
+func TestObserverPanic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	newGen := func(obs Observer, policy PanicPolicy) *Generator {
+		return New(&Config{
+			MaxBytes:    testConfig.MaxBytes,
+			MaxVals:     testConfig.MaxVals,
+			Source:      noopSource{true},
+			Observer:    obs,
+			PanicPolicy: policy,
+		}).WithTransformers(Plural)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		onExit bool
+	}{
+		{"Transform_End", false},
+		{"Layer_Exit", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newGen(panicObserver{onExit: tc.onExit}, PanicShutdown).Generate(context.Background(), "foo")
+			var perr *TransformerPanicError
+			if !errors.As(err, &perr) || perr.Value != "observer panic" {
+				t.Fatalf("expected observer panic error but got %v", err)
+			}
+		})
+	}
+
+	t.Run("Skip", func(t *testing.T) {
+		vals, err := newGen(panicObserver{}, PanicSkip).Generate(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vals) != 0 {
+			t.Fatalf("expected the observed messages to be skipped but got %v", vals)
+		}
+	})
+}
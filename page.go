@@ -0,0 +1,141 @@
+package sinoname
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Page holds a page of values generated by GeneratePage.
+type Page struct {
+	// Values are the values of the page, none of them was returned by the previous pages.
+	Values []string
+	// Status indicates how the pipeline ended, StatusFinished with no values means that
+	// there are no more values to fetch.
+	Status Status
+	// Next is the continuation token used to fetch the next page.
+	Next string
+}
+
+// pageToken is the content of a continuation token.
+type pageToken struct {
+	Version int    `json:"v"`
+	Input   string `json:"i"`
+	// Seen holds the values returned so far, the oldest first.
+	Seen []string `json:"s"`
+}
+
+const pageTokenVersion = 2
+
+// maxPageTokenValues caps the number of values recorded by a continuation token, past it
+// GeneratePage returns ErrPageLimit.
+const maxPageTokenValues = 512
+
+// GeneratePage passes the in field through the pipeline like GenerateDetailed, returning
+// a continuation token along side the values. Passing the token to the next call fetches
+// the next MaxVals values without repeating any value of the previous pages, pass an empty
+// token to fetch the first page.
+//
+// The token records the values returned so far, these values are treated as taken by
+// the source so that the transformers move on to their next candidates. The token grows
+// with each page up to 512 values: the page filling it is cut short to fit and the calls
+// with a full token return ErrPageLimit, values are never repeated. The token is opaque
+// and signed with the PageTokenKey config field, it is only valid for the same in field,
+// ErrInvalidToken is returned otherwise.
+//
+//	page, err := gen.GeneratePage(ctx, "lambels", "")
+//	// ...
+//	more, err := gen.GeneratePage(ctx, "lambels", page.Next)
+func (g *Generator) GeneratePage(ctx context.Context, in string, token string) (Page, error) {
+	seen, err := g.decodePageToken(in, token)
+	if err != nil {
+		return Page{Status: StatusAborted}, err
+	}
+
+	if len(seen) >= maxPageTokenValues {
+		return Page{Status: StatusAborted}, ErrPageLimit
+	}
+
+	taken := make(map[string]struct{}, len(seen))
+	for _, v := range seen {
+		taken[v] = struct{}{}
+	}
+
+	res, err := g.generateDetailed(ctx, in, generateState{seen: taken})
+	page := Page{
+		Values: res.Values,
+		Status: res.Status,
+	}
+	if err != nil {
+		return page, err
+	}
+
+	// the values which dont fit in the token would show up again on the next pages.
+	if room := maxPageTokenValues - len(seen); len(page.Values) > room {
+		page.Values = page.Values[:room]
+	}
+	seen = append(seen, page.Values...)
+	page.Next, err = g.encodePageToken(in, seen)
+	return page, err
+}
+
+// pageTokenKey returns the key signing the continuation tokens of the generator.
+func pageTokenKey(cfg *Config) []byte {
+	if len(cfg.PageTokenKey) > 0 {
+		return cfg.PageTokenKey
+	}
+
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// encodePageToken encodes the token as the signature of the payload followed by the
+// payload.
+func (g *Generator) encodePageToken(in string, seen []string) (string, error) {
+	b, err := json.Marshal(pageToken{
+		Version: pageTokenVersion,
+		Input:   in,
+		Seen:    seen,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, g.pageKey)
+	mac.Write(b)
+	return base64.RawURLEncoding.EncodeToString(append(mac.Sum(nil), b...)), nil
+}
+
+func (g *Generator) decodePageToken(in string, token string) ([]string, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) < sha256.Size {
+		return nil, ErrInvalidToken
+	}
+	sum, b := b[:sha256.Size], b[sha256.Size:]
+
+	mac := hmac.New(sha256.New, g.pageKey)
+	mac.Write(b)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return nil, ErrInvalidToken
+	}
+
+	var t pageToken
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if t.Version != pageTokenVersion || t.Input != in || len(t.Seen) > maxPageTokenValues {
+		return nil, ErrInvalidToken
+	}
+
+	return t.Seen, nil
+}
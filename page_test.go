@@ -0,0 +1,147 @@
+package sinoname
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestGeneratePage(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	gen := New(&Config{
+		MaxBytes:       testConfig.MaxBytes,
+		MaxVals:        2,
+		MaxChanges:     10,
+		PreventDefault: true,
+		Source:         noopSource{true},
+	}).WithTransformers(
+		IncrementalSuffix(3, "_"),
+		IncrementalPrefix(3, "_"),
+	)
+
+	t.Run("Pages", func(t *testing.T) {
+		var token string
+		for _, want := range [][]string{
+			{"1_abc", "abc_1"},
+			{"2_abc", "abc_2"},
+			{"3_abc", "abc_3"},
+			nil,
+		} {
+			page, err := gen.GeneratePage(context.Background(), "abc", token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Strings(page.Values)
+			if len(page.Values) != len(want) {
+				t.Fatalf("expected %v got %v", want, page.Values)
+			}
+			for i, v := range want {
+				if page.Values[i] != v {
+					t.Fatalf("expected %v got %v", want, page.Values)
+				}
+			}
+			token = page.Next
+		}
+	})
+
+	t.Run("Invalid_Token", func(t *testing.T) {
+		page, err := gen.GeneratePage(context.Background(), "abc", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := gen.GeneratePage(context.Background(), "abcd", page.Next); err != ErrInvalidToken {
+			t.Fatalf("expected %v got %v", ErrInvalidToken, err)
+		}
+		if _, err := gen.GeneratePage(context.Background(), "abc", "not a token"); err != ErrInvalidToken {
+			t.Fatalf("expected %v got %v", ErrInvalidToken, err)
+		}
+	})
+
+	t.Run("Altered_Token", func(t *testing.T) {
+		b, _ := json.Marshal(pageToken{Version: pageTokenVersion, Input: "abc", Seen: []string{"abc_1"}})
+		forged := base64.RawURLEncoding.EncodeToString(append(make([]byte, sha256.Size), b...))
+		if _, err := gen.GeneratePage(context.Background(), "abc", forged); err != ErrInvalidToken {
+			t.Fatalf("expected %v got %v", ErrInvalidToken, err)
+		}
+
+		// tokens of another generator are signed with another key.
+		other := New(&Config{MaxBytes: testConfig.MaxBytes, MaxVals: 2, Source: noopSource{true}})
+		token, err := other.encodePageToken("abc", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := gen.GeneratePage(context.Background(), "abc", token); err != ErrInvalidToken {
+			t.Fatalf("expected %v got %v", ErrInvalidToken, err)
+		}
+	})
+
+	t.Run("Shared_Key", func(t *testing.T) {
+		newGen := func() *Generator {
+			return New(&Config{
+				MaxBytes:     testConfig.MaxBytes,
+				MaxVals:      2,
+				MaxChanges:   10,
+				Source:       noopSource{true},
+				PageTokenKey: []byte("secret"),
+			}).WithTransformers(IncrementalSuffix(3, "_"))
+		}
+
+		page, err := newGen().GeneratePage(context.Background(), "abc", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := newGen().GeneratePage(context.Background(), "abc", page.Next); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Full_Token", func(t *testing.T) {
+		gen := New(&Config{
+			MaxBytes:       testConfig.MaxBytes,
+			MaxVals:        3,
+			MaxChanges:     10,
+			PreventDefault: true,
+			Source:         noopSource{true},
+		}).WithTransformers(IncrementalSuffix(maxPageTokenValues+3, "_"))
+
+		seen := make([]string, maxPageTokenValues-1)
+		for i := range seen {
+			seen[i] = "abc_" + strconv.Itoa(i+1)
+		}
+		token, err := gen.encodePageToken("abc", seen)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the page is cut short to the room left in the token.
+		page, err := gen.GeneratePage(context.Background(), "abc", token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "abc_" + strconv.Itoa(maxPageTokenValues)
+		if len(page.Values) != 1 || page.Values[0] != want {
+			t.Fatalf("expected [%v] got %v", want, page.Values)
+		}
+
+		seen, err = gen.decodePageToken("abc", page.Next)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(seen) != maxPageTokenValues || seen[0] != "abc_1" || seen[len(seen)-1] != want {
+			t.Fatalf("unexpected token values %v ... %v", seen[0], seen[len(seen)-1])
+		}
+
+		if _, err := gen.GeneratePage(context.Background(), "abc", page.Next); err != ErrPageLimit {
+			t.Fatalf("expected %v got %v", ErrPageLimit, err)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// PooledGenerator runs the pipeline of a generator on a pool of reusable go-routines.
+//
+// Each Generate call on a Generator starts fresh go-routines for the layers and for each
+// transformer call, growing their stacks over and over again. A PooledGenerator keeps
+// the go-routines alive between calls so that their stacks are reused.
+//
+// Only the go-routines are pooled: the channels, broadcasters and statefull transformers
+// are still built on each call, so a call allocates about as much as a Generator call.
+// The behaviour of the pipeline is identical.
+//
+// A PooledGenerator is safe for concurrent use, it must be closed once not needed.
+type PooledGenerator struct {
+	g    *Generator
+	pool *workerPool
+}
+
+// Pooled returns a PooledGenerator running the pipeline of the generator on pooled
+// go-routines. Layers must not be added to the generator after this call.
+//
+// maxIdle limits the number of idle workers kept alive between calls, if maxIdle <= 0
+// 64 * runtime.GOMAXPROCS(0) workers are kept alive.
+func (g *Generator) Pooled(maxIdle int) *PooledGenerator {
+	if maxIdle <= 0 {
+		maxIdle = 64 * runtime.GOMAXPROCS(0)
+	}
+
+	return &PooledGenerator{
+		g:    g,
+		pool: newWorkerPool(maxIdle),
+	}
+}
+
+// Generate passes the in field through the pipeline like (*Generator).Generate.
+func (p *PooledGenerator) Generate(ctx context.Context, in string) ([]string, error) {
+	return p.g.Generate(contextWithPool(ctx, p.pool), in)
+}
+
+// GenerateDetailed passes the in field through the pipeline like
+// (*Generator).GenerateDetailed.
+func (p *PooledGenerator) GenerateDetailed(ctx context.Context, in string) (Result, error) {
+	return p.g.GenerateDetailed(contextWithPool(ctx, p.pool), in)
+}
+
+// GeneratePage passes the in field through the pipeline like (*Generator).GeneratePage.
+func (p *PooledGenerator) GeneratePage(ctx context.Context, in string, token string) (Page, error) {
+	return p.g.GeneratePage(contextWithPool(ctx, p.pool), in, token)
+}
+
+// GenerateBatch passes the inputs through the pipeline like (*Generator).GenerateBatch.
+func (p *PooledGenerator) GenerateBatch(ctx context.Context, inputs []string) ([]BatchResult, error) {
+	return p.g.GenerateBatch(contextWithPool(ctx, p.pool), inputs)
+}
+
+// GenerateStream passes the inputs through the pipeline like (*Generator).GenerateStream.
+func (p *PooledGenerator) GenerateStream(ctx context.Context, inputs <-chan string) <-chan BatchResult {
+	return p.g.GenerateStream(contextWithPool(ctx, p.pool), inputs)
+}
+
+// DryRun passes the in field through the pipeline like (*Generator).DryRun.
+func (p *PooledGenerator) DryRun(ctx context.Context, in string, src Source) (DryRunReport, error) {
+	return p.g.DryRun(contextWithPool(ctx, p.pool), in, src)
+}
+
+// Close stops the idle workers, the workers still running stop once they finish.
+func (p *PooledGenerator) Close() {
+	p.pool.close()
+}
+
+// workerPool runs functions on reusable go-routines.
+//
+// Submitting a function never blocks: if no worker is idle a new worker is started. This
+// is important since the functions of the pipeline block on each other, a bounded pool
+// could deadlock the pipeline.
+type workerPool struct {
+	jobs    chan func()
+	quit    chan struct{}
+	once    sync.Once
+	idle    atomic.Int32
+	maxIdle int32
+}
+
+func newWorkerPool(maxIdle int) *workerPool {
+	return &workerPool{
+		jobs:    make(chan func()),
+		quit:    make(chan struct{}),
+		maxIdle: int32(maxIdle),
+	}
+}
+
+// submit runs f on an idle worker or on a new worker.
+func (p *workerPool) submit(f func()) {
+	select {
+	case p.jobs <- f:
+	default:
+		go p.work(f)
+	}
+}
+
+func (p *workerPool) work(f func()) {
+	for {
+		f()
+
+		if p.idle.Add(1) > p.maxIdle {
+			p.idle.Add(-1)
+			return
+		}
+		select {
+		case <-p.quit:
+			p.idle.Add(-1)
+			return
+		case f = <-p.jobs:
+			p.idle.Add(-1)
+		}
+	}
+}
+
+func (p *workerPool) close() {
+	p.once.Do(func() {
+		close(p.quit)
+	})
+}
+
+type poolKey struct{}
+
+func contextWithPool(ctx context.Context, p *workerPool) context.Context {
+	return context.WithValue(ctx, poolKey{}, p)
+}
+
+func poolFromContext(ctx context.Context) *workerPool {
+	p, _ := ctx.Value(poolKey{}).(*workerPool)
+	return p
+}
+
+// pooledGroup is the errgroup of the go-routines ran on the worker pool for one pipeline
+// run. The first error cancels the pipeline.
+//
+// Context errors returned once ctx is done are caused by the shutdown of the pipeline,
+// they are dropped so that they dont hide the error which caused it.
+type pooledGroup struct {
+	pool   *workerPool
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg      sync.WaitGroup
+	errOnce sync.Once
+	err     error
+}
+
+func newPooledGroup(pool *workerPool, ctx context.Context, cancel context.CancelFunc) *pooledGroup {
+	return &pooledGroup{
+		pool:   pool,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (g *pooledGroup) Go(f func() error) {
+	g.wg.Add(1)
+	g.pool.submit(func() {
+		defer g.wg.Done()
+
+		err := f()
+		if ctxErr := g.ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return
+		}
+		if err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	})
+}
+
+// Wait waits for all the go-routines of the group and returns the first error.
+func (g *pooledGroup) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+type groupKey struct{}
+
+func contextWithGroup(ctx context.Context, g *pooledGroup) context.Context {
+	return context.WithValue(ctx, groupKey{}, g)
+}
+
+// runner runs the go-routines of the layers, errgroup.Group and pooledGroup implement it.
+type runner interface {
+	Go(f func() error)
+}
+
+// runnerFor returns the runner of the pipeline ran with ctx: the pooled group of a
+// PooledGenerator or g. The spawn function runs go-routines which arent tracked by the
+// runner.
+func runnerFor(ctx context.Context, g *errgroup.Group) (runner, func(func())) {
+	pg, ok := ctx.Value(groupKey{}).(*pooledGroup)
+	if !ok {
+		return g, func(f func()) { go f() }
+	}
+
+	return pg, pg.pool.submit
+}
@@ -0,0 +1,170 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func newPoolTestGenerator(src Source, maxVals int) *Generator {
+	return New(&Config{
+		MaxBytes:   testConfig.MaxBytes,
+		MaxVals:    maxVals,
+		MaxChanges: 10,
+		Source:     src,
+	}).WithTransformers(
+		CamelCase,
+		SnakeCase,
+		KebabCase,
+		newStatefullTransformer(),
+	).WithUniformTransformers(
+		NumbersSuffix(""),
+		Plural,
+		Noop,
+	)
+}
+
+func TestPooledGenerator(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("Identical", func(t *testing.T) {
+		gen := newPoolTestGenerator(noopSource{true}, 100)
+		pooled := gen.Pooled(0)
+		defer pooled.Close()
+
+		for _, in := range []string{"foo.bar2006", "lam bels", "x"} {
+			want, err := gen.Generate(context.Background(), in)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// run multiple times to reuse the workers and the statefull transformers.
+			for i := 0; i < 3; i++ {
+				got, err := pooled.Generate(context.Background(), in)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				sort.Strings(want)
+				sort.Strings(got)
+				if len(got) != len(want) {
+					t.Fatalf("expected %v got %v", want, got)
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Fatalf("expected %v got %v", want, got)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Max_Vals", func(t *testing.T) {
+		pooled := newPoolTestGenerator(noopSource{true}, 3).Pooled(0)
+		defer pooled.Close()
+
+		res, err := pooled.GenerateDetailed(context.Background(), "foo.bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Values) != 3 || res.Status != StatusMaxVals {
+			t.Fatalf("expected 3 values and %v got %v and %v", StatusMaxVals, res.Values, res.Status)
+		}
+	})
+
+	t.Run("Forwarded", func(t *testing.T) {
+		gen := newPoolTestGenerator(noopSource{true}, 100)
+		pooled := gen.Pooled(0)
+		defer pooled.Close()
+
+		want, err := gen.DryRun(context.Background(), "foo.bar", AlwaysTaken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := pooled.DryRun(context.Background(), "foo.bar", AlwaysTaken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Values != want.Values || got.Distinct != want.Distinct {
+			t.Fatalf("expected %v values got %v", want.Values, got.Values)
+		}
+
+		results, err := pooled.GenerateBatch(context.Background(), []string{"foo", "bar"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, res := range results {
+			if res.Err != nil || len(res.Values) == 0 {
+				t.Fatalf("unexpected result %+v", res)
+			}
+		}
+
+		page, err := pooled.GeneratePage(context.Background(), "foo", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pooled.GeneratePage(context.Background(), "foo", page.Next); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		errSrc := errors.New("source error")
+		pooled := newPoolTestGenerator(errSource{errSrc}, 100).Pooled(1)
+		defer pooled.Close()
+
+		for i := 0; i < 3; i++ {
+			_, err := pooled.Generate(context.Background(), "foo")
+			var perr *PipelineError
+			if !errors.As(err, &perr) || !errors.Is(err, errSrc) {
+				t.Fatalf("expected *PipelineError wrapping %v got %v", errSrc, err)
+			}
+		}
+	})
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	gen := newPoolTestGenerator(noopSource{true}, 100)
+	benchmarkGenerate(b, gen.Generate)
+}
+
+func BenchmarkPooledGenerate(b *testing.B) {
+	pooled := newPoolTestGenerator(noopSource{true}, 100).Pooled(0)
+	defer pooled.Close()
+	benchmarkGenerate(b, pooled.Generate)
+}
+
+func BenchmarkGenerateParallel(b *testing.B) {
+	gen := newPoolTestGenerator(noopSource{true}, 100)
+	benchmarkGenerateParallel(b, gen.Generate)
+}
+
+func BenchmarkPooledGenerateParallel(b *testing.B) {
+	pooled := newPoolTestGenerator(noopSource{true}, 100).Pooled(0)
+	defer pooled.Close()
+	benchmarkGenerateParallel(b, pooled.Generate)
+}
+
+func benchmarkGenerate(b *testing.B, generate func(context.Context, string) ([]string, error)) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := generate(context.Background(), "foo.bar2006"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkGenerateParallel(b *testing.B, generate func(context.Context, string) ([]string, error)) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := generate(context.Background(), "foo.bar2006"); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package rng
+
+import (
+	"math/bits"
+)
+
+var _ PRNG = (*AffineGen)(nil)
+
+// AffineGen generates the numbers in the range [0, n) uniquely in a pseudo-random order
+// via the affine map i -> (a * i + b) mod n, with a coprime to n.
+//
+// Unlike UniqueRangeGen it doesent keep track of the generated numbers, it uses constant
+// memory whatever the range, but the order is less random: two consecutive numbers
+// always differ by a (mod n).
+type AffineGen struct {
+	n, a, b uint64
+	i       uint64
+}
+
+// NewAffineGen returns an affine generator for the range [0, n) initialized with the seed.
+func NewAffineGen(seed, n int) *AffineGen {
+	g := &AffineGen{
+		n: uint64(n),
+	}
+	g.Seed(seed)
+	return g
+}
+
+// Next generates the next number, it never generates the same number twice. The boolean
+// is true once all the numbers in the range were generated.
+func (g *AffineGen) Next() (int, bool) {
+	if g.i >= g.n {
+		return 0, true
+	}
+
+	v := g.At(int(g.i))
+	g.i++
+	return v, g.i == g.n
+}
+
+// At returns the i-th number generated by the generator, without advancing it.
+func (g *AffineGen) At(i int) int {
+	// a, i < n so the high bits of a * i are < n.
+	hi, lo := bits.Mul64(g.a, uint64(i))
+	_, rem := bits.Div64(hi, lo, g.n)
+	// rem + b cant overflow since both are < n.
+	return int((rem + g.b) % g.n)
+}
+
+// Seed picks new a and b from the seed and restarts the range.
+func (g *AffineGen) Seed(seed int) {
+	g.i = 0
+	if g.n <= 1 {
+		g.a, g.b = 1, 0
+		return
+	}
+
+	x := splitmix64(uint64(seed))
+	g.b = x % g.n
+	g.a = 1 + splitmix64(x)%(g.n-1)
+	for gcd(g.a, g.n) != 1 {
+		g.a = 1 + g.a%(g.n-1)
+	}
+}
+
+// Range outputs n. [0, n)
+func (g *AffineGen) Range() int {
+	return int(g.n)
+}
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
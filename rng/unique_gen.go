@@ -22,23 +22,29 @@ func NewUniqueRangeGen(src *rand.Rand, n int) *UniqueRangeGen {
 	}
 }
 
-// Next advances the generator and generates a new number.
+// Next advances the generator and generates a new number, it never generates the same
+// number twice. The boolean is true once all the numbers in the range were generated.
 func (g *UniqueRangeGen) Next() (int, bool) {
 	if len(g.vals) == g.n {
 		return 0, true
 	}
 
 	r := g.src.Intn(g.n)
-	for _, ok := g.vals[r]; !ok; {
+	for {
+		if _, ok := g.vals[r]; !ok {
+			break
+		}
 		r = g.src.Intn(g.n)
 	}
+	g.vals[r] = struct{}{}
 
 	return r, len(g.vals) == g.n
 }
 
-// Seed re seeds the source.
+// Seed re seeds the source and restarts the range.
 func (g *UniqueRangeGen) Seed(seed int) {
 	g.src.Seed(int64(seed))
+	g.vals = make(map[int]struct{})
 }
 
 // Range outputs n. [0, n)
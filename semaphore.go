@@ -0,0 +1,106 @@
+package sinoname
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// semaphore limits the number of transformers running at the same time.
+//
+// A nil semaphore has no limit.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+
+	return make(semaphore, n)
+}
+
+// acquire takes one slot from the semaphore, blocking till a slot is available or the
+// context is cancelled. It returns false if the context was cancelled.
+func (s semaphore) acquire(ctx context.Context) bool {
+	if s == nil {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case s <- struct{}{}:
+		return true
+	}
+}
+
+// release gives back one slot to the semaphore.
+func (s semaphore) release() {
+	if s == nil {
+		return
+	}
+
+	<-s
+}
+
+type semaphoreKey struct{}
+
+// contextWithSemaphore adds the generate-wide semaphore to the context, it is shared by
+// all the layers of the pipeline.
+func contextWithSemaphore(ctx context.Context, s semaphore) context.Context {
+	if s == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, semaphoreKey{}, s)
+}
+
+// semaphoreFromContext gets the generate-wide semaphore from the context.
+func semaphoreFromContext(ctx context.Context) semaphore {
+	s, _ := ctx.Value(semaphoreKey{}).(semaphore)
+	return s
+}
+
+// slot is the semaphore slot of one transformer call. It is released once the call and
+// all the go-routines running the transformer returned, so that transformers which time
+// out keep counting against the concurrency limits till they actually stop.
+type slot struct {
+	refs    atomic.Int32
+	release func()
+}
+
+func newSlot(release func()) *slot {
+	s := &slot{release: release}
+	s.refs.Add(1)
+	return s
+}
+
+// hold keeps the slot taken till the matching done call.
+func (s *slot) hold() {
+	if s == nil {
+		return
+	}
+
+	s.refs.Add(1)
+}
+
+// done releases the slot once all the holders are done.
+func (s *slot) done() {
+	if s == nil {
+		return
+	}
+
+	if s.refs.Add(-1) == 0 {
+		s.release()
+	}
+}
+
+type slotKey struct{}
+
+func contextWithSlot(ctx context.Context, s *slot) context.Context {
+	return context.WithValue(ctx, slotKey{}, s)
+}
+
+func slotFromContext(ctx context.Context) *slot {
+	s, _ := ctx.Value(slotKey{}).(*slot)
+	return s
+}
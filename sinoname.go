@@ -2,13 +2,15 @@ package sinoname
 
 import (
 	"context"
-	"errors"
 	"sync"
+	"time"
 )
 
 // Generator provides extra functionality on top of the layers.
 type Generator struct {
 	cfg *Config
+	// pageKey signs the continuation tokens.
+	pageKey []byte
 
 	layers Layers
 }
@@ -36,6 +38,13 @@ func New(conf *Config) *Generator {
 	if conf.StripNumbers == nil {
 		conf.StripNumbers = stripNumbersASCII
 	}
+	// the source is wrapped even if nil so that DryRun can override it.
+	if _, ok := conf.Source.(*pipelineSource); !ok {
+		conf.Source = &pipelineSource{
+			src: conf.Source,
+			cfg: conf,
+		}
+	}
 
 	// if adjectives provided, create a pool to share shuffle buffers around all circumfix,
 	// suffix or prefix transformer go routines.
@@ -50,7 +59,8 @@ func New(conf *Config) *Generator {
 	}
 
 	g := &Generator{
-		cfg: conf,
+		cfg:     conf,
+		pageKey: pageTokenKey(conf),
 	}
 
 	return g
@@ -58,22 +68,7 @@ func New(conf *Config) *Generator {
 
 // WithUniformTransformers adds the provided transformers in a uniform layer.
 func (g *Generator) WithUniformTransformers(tFact ...TransformerFactory) *Generator {
-	uLayer := &UniformTransformerLayer{
-		cfg:                  g.cfg,
-		transformers:         make([]Transformer, len(tFact)),
-		transformerFactories: make([]TransformerFactory, 0),
-	}
-
-	for i, f := range tFact {
-		t, statefull := f(g.cfg)
-		if statefull {
-			uLayer.transformerFactories = append(uLayer.transformerFactories, f)
-		}
-		uLayer.transformers[i] = t
-	}
-	g.layers = append(g.layers, uLayer)
-
-	return g
+	return g.WithLayers(UniformTransformers(tFact...))
 }
 
 // WithTransformers adds the provided transformers in a layer (grouped together).
@@ -81,22 +76,7 @@ func (g *Generator) WithUniformTransformers(tFact ...TransformerFactory) *Genera
 // This is the layer configuration which suits most use-cases, you should generally look
 // no further.
 func (g *Generator) WithTransformers(tFact ...TransformerFactory) *Generator {
-	tLayer := &TransformerLayer{
-		cfg:                  g.cfg,
-		transformers:         make([]Transformer, len(tFact)),
-		transformerFactories: make([]TransformerFactory, 0),
-	}
-
-	for i, f := range tFact {
-		t, statefull := f(g.cfg)
-		if statefull {
-			tLayer.transformerFactories = append(tLayer.transformerFactories, f)
-		}
-		tLayer.transformers[i] = t
-	}
-	g.layers = append(g.layers, tLayer)
-
-	return g
+	return g.WithLayers(Transformers(tFact...))
 }
 
 // WithLayers adds the provided layers to the generator in order.
@@ -109,11 +89,132 @@ func (g *Generator) WithLayers(lFact ...LayerFactory) *Generator {
 	return g
 }
 
+// Timeouts returns the number of transformer calls which timed out since the generator
+// was created (see Config.TransformerTimeout and Timeout).
+func (g *Generator) Timeouts() uint64 {
+	return g.cfg.timeouts.Load()
+}
+
+// Status describes how a Generate call ended.
+type Status int
+
+const (
+	// StatusFinished indicates that the pipeline sent out all its values.
+	StatusFinished Status = iota
+	// StatusMaxVals indicates that the pipeline was cut short after reading MaxVals values.
+	StatusMaxVals
+	// StatusAborted indicates that the pipeline was aborted by an error or by the context.
+	StatusAborted
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusFinished:
+		return "finished"
+	case StatusMaxVals:
+		return "max vals"
+	case StatusAborted:
+		return "aborted"
+	default:
+		return "unknown"
+	}
+}
+
+// Result holds the values read from the pipeline and how the pipeline ended.
+type Result struct {
+	// Values are the values read from the pipeline, in the order they were read.
+	Values []string
+	// Status indicates how the pipeline ended.
+	Status Status
+}
+
 // Generate passes the in field through the pipeline of transformers. The process can be
 // aborted by cancelling the context passed.
+//
+// If the pipeline is aborted by an error no values are returned, use GenerateDetailed to
+// keep the values read before the error.
 func (g *Generator) Generate(ctx context.Context, in string) ([]string, error) {
+	res, err := g.GenerateDetailed(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Values, nil
+}
+
+// GenerateDetailed passes the in field through the pipeline of transformers like Generate.
+//
+// Unlike Generate, the values read before an error are kept, the returned result always
+// holds the values read along side the status of the pipeline:
+//
+//	res, err := gen.GenerateDetailed(ctx, "lambels")
+//	if err != nil {
+//		log.Println(err) // res.Status == sinoname.StatusAborted
+//	}
+//	suggest(res.Values)
+func (g *Generator) GenerateDetailed(ctx context.Context, in string) (Result, error) {
+	return g.generateDetailed(ctx, in, generateState{})
+}
+
+// generateState holds the state of one generate call shared with other calls.
+type generateState struct {
+	// seen values are treated as taken by the source and never read.
+	seen map[string]struct{}
+	// claims, if set, is shared with other generate calls, a value is only read if it
+	// can be claimed by owner.
+	claims *claimSet
+	owner  int
+}
+
+func (g *Generator) generateDetailed(ctx context.Context, in string, state generateState) (Result, error) {
+	start := time.Now()
+	res, err := g.generate(ctx, in, state)
+	oErr := observe(ctx, g.cfg, func() {
+		g.cfg.observer().OnGenerateDone(ctx, GenerateEvent{
+			Input:    in,
+			Result:   res,
+			Err:      err,
+			Duration: time.Since(start),
+		})
+	})
+	// the values are already generated, there is no message left to skip.
+	if oErr != nil && oErr != ErrSkip && err == nil {
+		err = oErr
+	}
+
+	return res, err
+}
+
+// source wraps src so that the seen and claimed values are taken, it returns false if
+// there is no need to wrap src.
+func (s generateState) source(src Source) (Source, bool) {
+	if len(s.seen) == 0 && s.claims == nil {
+		return src, false
+	}
+
+	if len(s.seen) > 0 {
+		src = &takenSource{
+			taken: s.seen,
+			src:   src,
+		}
+	}
+	if s.claims != nil {
+		src = &claimedSource{
+			claims: s.claims,
+			owner:  s.owner,
+			src:    src,
+		}
+	}
+	return src, true
+}
+
+func (g *Generator) generate(ctx context.Context, in string, state generateState) (Result, error) {
+	res := Result{
+		Status: StatusAborted,
+	}
+	in = g.cfg.normalize(in)
 	if len(in) > g.cfg.MaxBytes {
-		return nil, errors.New("sinoname: value is too long")
+		return res, ErrTooLong
 	}
 
 	msgPacket := MessagePacket{
@@ -121,57 +222,70 @@ func (g *Generator) Generate(ctx context.Context, in string) ([]string, error) {
 		Changes: 0,
 		Skip:    0,
 	}
+	// the semaphore may be shared with other generate calls (see GenerateBatch).
+	if semaphoreFromContext(ctx) == nil {
+		ctx = contextWithSemaphore(ctx, newSemaphore(g.cfg.MaxConcurrency))
+	}
+	ctx = contextWithRandomNumber(ctx, g.cfg)
+	if src, ok := state.source(g.cfg.source()); ok {
+		ctx = contextWithSource(ctx, src)
+	}
+	if state.claims != nil {
+		defer state.claims.release(state.owner)
+	}
 	inC, clnUp, err := g.layers.Run(ctx, msgPacket)
 	if err != nil {
 		clnUp()
-		return nil, err
+		return res, err
 	}
 
-	var read int
-	var vals []string
 	readVals := make(map[string]bool)
 	readVals[in] = g.cfg.PreventDefault
+	for v := range state.seen {
+		readVals[v] = true
+	}
 L:
 	for {
 		select {
 		// if ctx cancelled no need to call clean up.
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			res.Status = StatusAborted
+			return res, ctx.Err()
 
 		case val, ok := <-inC:
+			if !ok {
+				res.Status = StatusFinished
+				break L
+			}
+
 			if readVals[val.Message] {
 				continue
 			}
+			if state.claims != nil && !state.claims.finalize(val.Message, state.owner) {
+				continue
+			}
 			if g.cfg.PreventDuplicates {
 				readVals[val.Message] = true
 			}
 
-			// increment read here so we dont have to wait for next itteration
-			// to check if we are at the last value.
-			//
-			// the next itteration can be slow.
-			read++
-
-			if read == g.cfg.MaxVals || !ok {
-				// last value.
-				if ok {
-					vals = append(vals, val.Message)
-				}
+			res.Values = append(res.Values, val.Message)
+			if len(res.Values) == g.cfg.MaxVals {
+				res.Status = StatusMaxVals
 				break L
 			}
-			vals = append(vals, val.Message)
 		}
 	}
 
 	if err := clnUp(); err != nil {
 		// this exception occurs when the maxVals value is reached and
 		// there still are live layers.
-		if err == context.Canceled {
-			return vals, nil
+		if err == context.Canceled && ctx.Err() == nil {
+			return res, nil
 		}
 
-		return nil, err
+		res.Status = StatusAborted
+		return res, err
 	}
 
-	return vals, nil
+	return res, nil
 }
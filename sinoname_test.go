@@ -2,6 +2,13 @@ package sinoname
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
 )
 
 var testConfig = &Config{
@@ -47,3 +54,80 @@ func newStaticSource(vals ...string) *staticSrc {
 		vals: vals,
 	}
 }
+
+type failAfterSource struct {
+	n   int32
+	err error
+}
+
+func (s *failAfterSource) Valid(ctx context.Context, in string) (bool, error) {
+	if atomic.AddInt32(&s.n, -1) < 0 {
+		return false, s.err
+	}
+
+	return true, nil
+}
+
+func TestGenerateDetailed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	newConfig := func(src Source, maxVals int) *Config {
+		return &Config{
+			MaxBytes:   testConfig.MaxBytes,
+			MaxVals:    maxVals,
+			MaxChanges: 10,
+			Source:     src,
+		}
+	}
+
+	t.Run("Finished", func(t *testing.T) {
+		gen := New(newConfig(noopSource{true}, 10)).WithTransformers(Plural, Noop)
+
+		res, err := gen.GenerateDetailed(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != StatusFinished || len(res.Values) != 2 {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("Max_Vals", func(t *testing.T) {
+		gen := New(newConfig(noopSource{true}, 1)).WithTransformers(Plural, Noop)
+
+		res, err := gen.GenerateDetailed(context.Background(), "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != StatusMaxVals || len(res.Values) != 1 {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+	})
+
+	t.Run("Aborted", func(t *testing.T) {
+		errDB := errors.New("database down")
+		isSlow := func(_ context.Context, v MessagePacket) bool {
+			return strings.HasSuffix(v.Message, "_slow")
+		}
+		// the first source call passes, the second one (after the slow transformer) fails.
+		gen := New(newConfig(&failAfterSource{n: 1, err: errDB}, 10)).WithTransformers(
+			Plural,
+			newTimeoutTransformer("_slow", 50*time.Millisecond),
+		).WithLayers(Router(
+			Transformers(Noop),
+			When(isSlow, Plural),
+		))
+
+		res, err := gen.GenerateDetailed(context.Background(), "foo")
+		if !errors.Is(err, errDB) {
+			t.Fatalf("expected source error but got %v", err)
+		}
+		if res.Status != StatusAborted || len(res.Values) != 1 || res.Values[0] != "foos" {
+			t.Fatalf("expected the values read before the error but got: %+v", res)
+		}
+
+		if vals, err := gen.Generate(context.Background(), "foo"); err == nil || vals != nil {
+			t.Fatalf("expected Generate to discard the values but got %v, %v", vals, err)
+		}
+	})
+}
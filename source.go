@@ -1,6 +1,9 @@
 package sinoname
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Source is an interface which should be implemented by the client, it serves the purpose
 // of validating a username and must be concurrency safe.
@@ -16,3 +19,56 @@ type Source interface {
 	// The whole pipeline is closed if the source returns an error, rendering it unreliable.
 	Valid(context.Context, string) (bool, error)
 }
+
+// pipelineSource wraps the configured source. It lets pipeline runs override the source
+// via the context (see DryRun) and reports all the calls to the source to the observer.
+type pipelineSource struct {
+	src Source
+	cfg *Config
+}
+
+func (s *pipelineSource) Valid(ctx context.Context, v string) (bool, error) {
+	src := s.src
+	if override, ok := sourceFromContext(ctx); ok {
+		src = override
+	}
+
+	obs := observerFor(ctx, s.cfg)
+	if _, ok := obs.(NopObserver); ok {
+		return src.Valid(ctx, v)
+	}
+
+	start := time.Now()
+	ok, err := src.Valid(ctx, v)
+	oErr := observe(ctx, s.cfg, func() {
+		obs.OnSourceCall(ctx, SourceEvent{
+			Layer:       layerFromContext(ctx),
+			LayerPath:   layerPathFromContext(ctx),
+			Index:       transformerFromContext(ctx),
+			Transformer: transformerNameFromContext(ctx),
+			Value:       v,
+			Valid:       ok,
+			Err:         err,
+			Duration:    time.Since(start),
+		})
+	})
+	if oErr != nil && err == nil {
+		return false, oErr
+	}
+
+	return ok, err
+}
+
+// takenSource rejects the taken values without calling the source.
+type takenSource struct {
+	taken map[string]struct{}
+	src   Source
+}
+
+func (s *takenSource) Valid(ctx context.Context, v string) (bool, error) {
+	if _, ok := s.taken[v]; ok {
+		return false, nil
+	}
+
+	return s.src.Valid(ctx, v)
+}
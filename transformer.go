@@ -33,12 +33,6 @@ type Transformer interface {
 // is provided and suported by all layers.
 type TransformerFactory func(cfg *Config) (Transformer, bool)
 
-// errTooLong is a sentinel error. Used by applyAffix to differentiate between false ok value
-// returned from source or by the value being too long.
-//
-// errTooLong should not be returned by transformers, but be used to make further judgement.
-var errTooLong = errors.New("value too long")
-
 type affix int
 
 const (
@@ -47,6 +41,18 @@ const (
 	circumfix
 )
 
+// String returns the name of the affix as used in the names of the transformer factories.
+func (a affix) String() string {
+	switch a {
+	case prefix:
+		return "Prefix"
+	case circumfix:
+		return "Circumfix"
+	default:
+		return "Suffix"
+	}
+}
+
 func applyAffixFromPRNG(ctx context.Context, cfg *Config, gen rng.PRNG, nVals int, where affix, base MessagePacket, sep string, f func(int) string) (MessagePacket, error) {
 	offsets := nVals / gen.Range()
 
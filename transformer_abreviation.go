@@ -2,6 +2,7 @@ package sinoname
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -76,6 +77,16 @@ type abreviationTransformer struct {
 	where affix
 }
 
+func (t *abreviationTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Abreviation" + t.where.String(),
+		Params: []Param{
+			{"sep", strconv.Quote(t.sep)},
+			{"all", strconv.FormatBool(t.all)},
+		},
+	}
+}
+
 func (t *abreviationTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	if len(in.Message) > t.cfg.MaxBytes {
 		return in, nil
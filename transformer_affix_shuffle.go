@@ -2,19 +2,21 @@ package sinoname
 
 import (
 	"context"
+	"strconv"
 )
 
 // Prefix adds a prefix to the string.
 //
 // The prefix is obtained:
-//  1. From the context via the StringFromContext method.
+//  1. From the context via the hints, in order (StringFromContext if no hints are provided).
 //  2. At random from the adjectives array provided in the config object.
-var Prefix = func(sep string) func(cfg *Config) (Transformer, bool) {
+var Prefix = func(sep string, hints ...StringHint) func(cfg *Config) (Transformer, bool) {
 	return func(cfg *Config) (Transformer, bool) {
 		return &affixShuffleTransformer{
 			where: prefix,
 			cfg:   cfg,
 			sep:   sep,
+			hints: stringHints(hints),
 		}, false
 	}
 }
@@ -22,14 +24,15 @@ var Prefix = func(sep string) func(cfg *Config) (Transformer, bool) {
 // Suffix adds a suffix to the string.
 //
 // The suffix is obtained:
-//  1. From the context via the StringFromContext method.
+//  1. From the context via the hints, in order (StringFromContext if no hints are provided).
 //  2. At random from the adjectives array provided in the config object.
-var Suffix = func(sep string) func(cfg *Config) (Transformer, bool) {
+var Suffix = func(sep string, hints ...StringHint) func(cfg *Config) (Transformer, bool) {
 	return func(cfg *Config) (Transformer, bool) {
 		return &affixShuffleTransformer{
 			where: suffix,
 			cfg:   cfg,
 			sep:   sep,
+			hints: stringHints(hints),
 		}, false
 	}
 }
@@ -37,14 +40,57 @@ var Suffix = func(sep string) func(cfg *Config) (Transformer, bool) {
 // Circumfix adds a circumfix to the string.
 //
 // The circumfix is obtained:
-//  1. From the context via the StringFromContext method.
+//  1. From the context via the hints, in order (StringFromContext if no hints are provided).
 //  2. At random from the adjectives array provided in the config object.
-var Circumfix = func(sep string) func(cfg *Config) (Transformer, bool) {
+var Circumfix = func(sep string, hints ...StringHint) func(cfg *Config) (Transformer, bool) {
 	return func(cfg *Config) (Transformer, bool) {
 		return &affixShuffleTransformer{
 			where: circumfix,
 			cfg:   cfg,
 			sep:   sep,
+			hints: stringHints(hints),
+		}, false
+	}
+}
+
+// PrefixFrom adds a prefix to the string like Prefix, the words are taken from the list
+// instead of the adjectives array provided in the config object.
+var PrefixFrom = func(list *WordList, sep string, hints ...StringHint) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		return &affixShuffleTransformer{
+			where: prefix,
+			cfg:   cfg,
+			sep:   sep,
+			hints: stringHints(hints),
+			list:  list,
+		}, false
+	}
+}
+
+// SuffixFrom adds a suffix to the string like Suffix, the words are taken from the list
+// instead of the adjectives array provided in the config object.
+var SuffixFrom = func(list *WordList, sep string, hints ...StringHint) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		return &affixShuffleTransformer{
+			where: suffix,
+			cfg:   cfg,
+			sep:   sep,
+			hints: stringHints(hints),
+			list:  list,
+		}, false
+	}
+}
+
+// CircumfixFrom adds a circumfix to the string like Circumfix, the words are taken from the list
+// instead of the adjectives array provided in the config object.
+var CircumfixFrom = func(list *WordList, sep string, hints ...StringHint) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		return &affixShuffleTransformer{
+			where: circumfix,
+			cfg:   cfg,
+			sep:   sep,
+			hints: stringHints(hints),
+			list:  list,
 		}, false
 	}
 }
@@ -53,21 +99,71 @@ type affixShuffleTransformer struct {
 	cfg   *Config
 	where affix
 	sep   string
+	hints []StringHint
+	// list replaces the adjectives of the config if non nil.
+	list *WordList
+}
+
+// stringHints returns the hints used by default if none are provided.
+func stringHints(hints []StringHint) []StringHint {
+	if len(hints) == 0 {
+		return []StringHint{HintString}
+	}
+	return hints
+}
+
+func (t *affixShuffleTransformer) Describe() TransformerDescription {
+	params := []Param{
+		{"sep", strconv.Quote(t.sep)},
+	}
+	if len(t.hints) != 1 || t.hints[0] != HintString {
+		params = append(params, Param{"hints", joinHints(t.hints)})
+	}
+	if t.list != nil {
+		params = append(params, Param{"list", strconv.Quote(t.list.Name())})
+	}
+
+	return TransformerDescription{
+		Name:   t.where.String(),
+		Params: params,
+	}
+}
+
+// Estimate returns the number of adjectives (and hinted strings) which fit in MaxBytes.
+func (t *affixShuffleTransformer) Estimate(ctx context.Context, in MessagePacket) int {
+	var n int
+	for _, v := range hintStrings(ctx, t.hints) {
+		if _, ok := applyAffix(t.cfg, t.where, in.Message, t.sep, v); ok {
+			n++
+		}
+	}
+	for _, adj := range t.words() {
+		if _, ok := applyAffix(t.cfg, t.where, in.Message, t.sep, adj); ok {
+			n++
+		}
+	}
+
+	return n
 }
 
 func (t *affixShuffleTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
-	if v, ok := StringFromContext(ctx); ok {
+	for _, v := range hintStrings(ctx, t.hints) {
 		out, ok := applyAffix(t.cfg, t.where, in.Message, t.sep, v)
+		if !ok {
+			continue
+		}
 
-		if ok {
-			unique, err := t.cfg.Source.Valid(ctx, out)
-			if err != nil || unique {
-				in.setAndIncrement(out)
-				return in, err
-			}
+		unique, err := t.cfg.Source.Valid(ctx, out)
+		if err != nil || unique {
+			in.setAndIncrement(out)
+			return in, err
 		}
 	}
 
+	if t.list != nil {
+		return t.transformFromList(ctx, in)
+	}
+
 	prng, clnup := t.cfg.GetPRNG(len(t.cfg.Adjectives))
 	defer clnup()
 
@@ -76,3 +172,42 @@ func (t *affixShuffleTransformer) Transform(ctx context.Context, in MessagePacke
 	}
 	return applyAffixFromPRNG(ctx, t.cfg, prng, len(t.cfg.Adjectives), t.where, in, t.sep, f)
 }
+
+// transformFromList walks the permutation of the list from a random offset.
+func (t *affixShuffleTransformer) transformFromList(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	n := t.list.Len()
+	if n == 0 {
+		return in, nil
+	}
+
+	offset := t.cfg.intn(n)
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			return in, ctx.Err()
+		default:
+		}
+
+		out, ok := applyAffix(t.cfg, t.where, in.Message, t.sep, t.list.Word((offset+i)%n))
+		if !ok {
+			continue
+		}
+
+		unique, err := t.cfg.Source.Valid(ctx, out)
+		if err != nil || unique {
+			in.setAndIncrement(out)
+			return in, err
+		}
+	}
+
+	return in, nil
+}
+
+// words returns the words used by the transformer.
+func (t *affixShuffleTransformer) words() []string {
+	if t.list != nil {
+		return t.list.words
+	}
+
+	return t.cfg.Adjectives
+}
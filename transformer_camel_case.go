@@ -18,6 +18,10 @@ type camelCaseTransformer struct {
 	cfg *Config
 }
 
+func (_ *camelCaseTransformer) Describe() TransformerDescription {
+	return TransformerDescription{Name: "CamelCase"}
+}
+
 func (t *camelCaseTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	if len(in.Message) > t.cfg.MaxBytes {
 		return in, nil
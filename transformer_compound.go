@@ -0,0 +1,220 @@
+package sinoname
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Lambels/sinoname/rng"
+)
+
+// DefaultCompoundTemplates are the templates used by Compound if none are provided.
+var DefaultCompoundTemplates = []string{
+	"{Adj}{Token}",
+	"{Token}{Noun}",
+	"{Adj}{Token}{Noun}",
+}
+
+// Compound combines an adjective, the string and a noun following the templates:
+// lambels -> QuietLambels, LambelsOtter, BraveLambelsFalcon .
+//
+// The templates are made of text and the placeholders {adj}, {noun} and {token} (the
+// string), the capitalized placeholders {Adj}, {Noun} and {Token} capitalize the value.
+// Unknown placeholders are kept as text.
+//
+// The templates are tried in order, the adjectives and nouns of each template are sampled
+// at random without repeats (see rng.AffineGen). If adjectives is nil the Adjectives
+// config field is used.
+var Compound = func(adjectives, nouns []string, templates ...string) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		adj, raw := adjectives, templates
+		if adj == nil {
+			adj = cfg.Adjectives
+		}
+		if len(raw) == 0 {
+			raw = DefaultCompoundTemplates
+		}
+
+		parsed := make([]compoundTemplate, len(raw))
+		for i, tmpl := range raw {
+			parsed[i] = parseCompoundTemplate(tmpl)
+		}
+
+		return &compoundTransformer{
+			cfg:        cfg,
+			adjectives: adj,
+			nouns:      nouns,
+			templates:  parsed,
+			raw:        raw,
+		}, false
+	}
+}
+
+type compoundTransformer struct {
+	cfg        *Config
+	adjectives []string
+	nouns      []string
+	templates  []compoundTemplate
+	raw        []string
+}
+
+type compoundPart int
+
+const (
+	compoundText compoundPart = iota
+	compoundAdj
+	compoundNoun
+	compoundToken
+)
+
+type compoundSegment struct {
+	part compoundPart
+	text string
+	// title capitalizes the value of the placeholder.
+	title bool
+}
+
+type compoundTemplate struct {
+	segments []compoundSegment
+	adj      bool
+	noun     bool
+}
+
+func parseCompoundTemplate(tmpl string) compoundTemplate {
+	var out compoundTemplate
+	for len(tmpl) > 0 {
+		start := strings.IndexByte(tmpl, '{')
+		end := strings.IndexByte(tmpl, '}')
+		if start < 0 || end < start {
+			out.segments = append(out.segments, compoundSegment{text: tmpl})
+			break
+		}
+		if start > 0 {
+			out.segments = append(out.segments, compoundSegment{text: tmpl[:start]})
+		}
+
+		name := tmpl[start+1 : end]
+		seg := compoundSegment{title: name != "" && unicode.IsUpper(rune(name[0]))}
+		switch strings.ToLower(name) {
+		case "adj":
+			seg.part = compoundAdj
+			out.adj = true
+		case "noun":
+			seg.part = compoundNoun
+			out.noun = true
+		case "token":
+			seg.part = compoundToken
+		default:
+			seg.text = tmpl[start : end+1]
+		}
+		out.segments = append(out.segments, seg)
+		tmpl = tmpl[end+1:]
+	}
+
+	return out
+}
+
+// fill fills the template with the adjective, noun and token.
+func (t compoundTemplate) fill(adj, noun, token string) string {
+	var b strings.Builder
+	for _, seg := range t.segments {
+		v := seg.text
+		switch seg.part {
+		case compoundAdj:
+			v = adj
+		case compoundNoun:
+			v = noun
+		case compoundToken:
+			v = token
+		}
+
+		if seg.title && seg.part != compoundText {
+			r, width := utf8.DecodeRuneInString(v)
+			b.WriteRune(unicode.ToUpper(r))
+			v = v[width:]
+		}
+		b.WriteString(v)
+	}
+
+	return b.String()
+}
+
+func (t *compoundTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Compound",
+		Params: []Param{
+			{"adjectives", strconv.Itoa(len(t.adjectives))},
+			{"nouns", strconv.Itoa(len(t.nouns))},
+			{"templates", strconv.Quote(strings.Join(t.raw, ","))},
+		},
+	}
+}
+
+// Estimate returns the number of combinations of all the templates.
+func (t *compoundTransformer) Estimate(_ context.Context, _ MessagePacket) int {
+	var n int
+	for _, tmpl := range t.templates {
+		n = addSat(n, t.combinations(tmpl))
+	}
+
+	return n
+}
+
+// combinations returns the number of adjective and noun combinations of the template.
+func (t *compoundTransformer) combinations(tmpl compoundTemplate) int {
+	n := 1
+	if tmpl.adj {
+		n = mulSat(n, len(t.adjectives))
+	}
+	if tmpl.noun {
+		n = mulSat(n, len(t.nouns))
+	}
+
+	return n
+}
+
+func (t *compoundTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	for _, tmpl := range t.templates {
+		n := t.combinations(tmpl)
+		if n == 0 {
+			continue
+		}
+
+		gen := rng.NewAffineGen(t.cfg.intn(math.MaxInt), n)
+		for done := false; !done; {
+			select {
+			case <-ctx.Done():
+				return in, ctx.Err()
+			default:
+			}
+
+			var i int
+			i, done = gen.Next()
+
+			var adj, noun string
+			if tmpl.adj {
+				adj = t.adjectives[i%len(t.adjectives)]
+				i /= len(t.adjectives)
+			}
+			if tmpl.noun {
+				noun = t.nouns[i]
+			}
+
+			out := tmpl.fill(adj, noun, in.Message)
+			if out == in.Message || (t.cfg.MaxBytes > 0 && len(out) > t.cfg.MaxBytes) {
+				continue
+			}
+
+			ok, err := t.cfg.Source.Valid(ctx, out)
+			if err != nil || ok {
+				in.setAndIncrement(out)
+				return in, err
+			}
+		}
+	}
+
+	return in, nil
+}
@@ -0,0 +1,119 @@
+package sinoname
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/Lambels/sinoname/rng"
+)
+
+func TestCompound(t *testing.T) {
+	t.Run("Templates", func(t *testing.T) {
+		for _, tc := range []struct {
+			name, tmpl, out string
+		}{
+			{"Title", "{Adj}{Token}{Noun}", "QuietLambelsOtter"},
+			{"Lower", "{adj}_{token}_{noun}", "quiet_lambels_otter"},
+			{"Unknown", "{Adj}{x}{Token}", "Quiet{x}Lambels"},
+			{"Text", "the {Noun}", "the Otter"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				tr, _ := Compound([]string{"quiet"}, []string{"otter"}, tc.tmpl)(testConfig)
+
+				out, err := tr.Transform(context.Background(), MessagePacket{Message: "lambels"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if out.Message != tc.out {
+					t.Fatalf("expected %v got %v", tc.out, out.Message)
+				}
+			})
+		}
+	})
+
+	t.Run("No_Repeats", func(t *testing.T) {
+		adjectives := []string{"quiet", "brave", "calm"}
+		nouns := []string{"otter", "falcon"}
+		src := newStaticSource()
+		tr, _ := Compound(adjectives, nouns, "{Adj}{Noun}")(&Config{MaxBytes: 100, Source: src})
+
+		// each call returns a new combination since the previous ones are taken.
+		for i := 0; i < len(adjectives)*len(nouns); i++ {
+			out, err := tr.Transform(context.Background(), MessagePacket{Message: "lambels"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Message == "lambels" {
+				t.Fatalf("expected a new combination on call %d", i)
+			}
+			src.addValue(out.Message)
+		}
+
+		out, err := tr.Transform(context.Background(), MessagePacket{Message: "lambels"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Message != "lambels" {
+			t.Fatalf("expected all combinations to be taken got %v", out.Message)
+		}
+	})
+
+	t.Run("Template_Order", func(t *testing.T) {
+		tr, _ := Compound([]string{"quiet"}, AnimalNouns)(&Config{
+			MaxBytes: 100,
+			Source:   newStaticSource("QuietLambels"),
+		})
+
+		out, err := tr.Transform(context.Background(), MessagePacket{Message: "lambels"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Message[:len("Lambels")] != "Lambels" {
+			t.Fatalf("expected the second template got %v", out.Message)
+		}
+	})
+
+	t.Run("Config_Adjectives", func(t *testing.T) {
+		tFact := Compound(nil, nil, "{Adj}{token}")
+		for _, adj := range []string{"quiet", "brave"} {
+			tr, _ := tFact(&Config{MaxBytes: 100, Source: noopSource{true}, Adjectives: []string{adj}})
+
+			out, err := tr.Transform(context.Background(), MessagePacket{Message: "x"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := capitalize(adj) + "x"; out.Message != want {
+				t.Fatalf("expected %v got %v", want, out.Message)
+			}
+		}
+	})
+
+	t.Run("Max_Bytes", func(t *testing.T) {
+		tr, _ := Compound([]string{"a", "abcdefgh"}, nil, "{adj}{token}")(&Config{MaxBytes: 4, Source: noopSource{true}})
+
+		out, err := tr.Transform(context.Background(), MessagePacket{Message: "abc"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Message != "aabc" {
+			t.Fatalf("expected aabc got %v", out.Message)
+		}
+	})
+}
+
+func TestUniqueRangeGen(t *testing.T) {
+	gen := rng.NewUniqueRangeGen(rand.New(rand.NewSource(1)), 50)
+	seen := make(map[int]bool)
+	for done := false; !done; {
+		var n int
+		n, done = gen.Next()
+		if seen[n] || n < 0 || n >= 50 {
+			t.Fatalf("unexpected value %v", n)
+		}
+		seen[n] = true
+	}
+	if len(seen) != 50 {
+		t.Fatalf("expected 50 values got %v", len(seen))
+	}
+}
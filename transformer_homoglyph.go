@@ -3,6 +3,7 @@ package sinoname
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 )
@@ -91,6 +92,15 @@ type homoglyphTransformer struct {
 	homoglyphs []ConfidenceMap
 }
 
+func (t *homoglyphTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Homoglyph",
+		Params: []Param{
+			{"maps", strconv.Itoa(len(t.homoglyphs))},
+		},
+	}
+}
+
 func (t *homoglyphTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	var maxConfidence int
 	for _, v := range t.homoglyphs {
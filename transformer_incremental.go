@@ -57,6 +57,27 @@ type incrementalTransformer struct {
 	sep   string
 }
 
+func (t *incrementalTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Incremental" + t.where.String(),
+		Params: []Param{
+			{"n", strconv.Itoa(t.n)},
+			{"sep", strconv.Quote(t.sep)},
+		},
+	}
+}
+
+// Estimate returns the number of increments which fit in MaxBytes, at most n.
+func (t *incrementalTransformer) Estimate(_ context.Context, in MessagePacket) int {
+	for i := 1; i <= t.n; i++ {
+		if _, ok := applyAffix(t.cfg, t.where, in.Message, t.sep, strconv.Itoa(i)); !ok {
+			return i - 1
+		}
+	}
+
+	return t.n
+}
+
 func (t *incrementalTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	for i := 1; i <= t.n; i++ {
 		add := strconv.Itoa(i)
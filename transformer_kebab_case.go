@@ -18,6 +18,10 @@ type kebabCaseTransformer struct {
 	cfg *Config
 }
 
+func (_ *kebabCaseTransformer) Describe() TransformerDescription {
+	return TransformerDescription{Name: "KebabCase"}
+}
+
 func (t *kebabCaseTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	split := t.cfg.Tokenize(in.Message)
 	out := strings.Join(split, "-")
@@ -0,0 +1,279 @@
+package sinoname
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LeetMap maps runes and sequences of runes to their leetspeak substitutions.
+//
+// The embedded ConfidenceMap maps single runes, Sequences maps strings of several runes
+// ("ck", "ph", ...) which take precedence over the single runes they contain. Each
+// confidence level of a mapping should map the rune (or sequence) to itself if it isnt
+// substituted at that level.
+type LeetMap struct {
+	ConfidenceMap
+	Sequences map[string][][]rune
+}
+
+// ASCIILeet maps ascii letters to their common leetspeak substitutions.
+var ASCIILeet LeetMap = LeetMap{
+	ConfidenceMap: ConfidenceMap{
+		Map: map[rune][][]rune{
+			'a': {{'4'}, {'@'}},
+			'b': {{'8'}, {'8'}},
+			'e': {{'3'}, {'3'}},
+			'g': {{'9'}, {'9'}},
+			'i': {{'1'}, {'!'}},
+			'l': {{'l'}, {'1'}},
+			'o': {{'0'}, {'0'}},
+			's': {{'5'}, {'$'}},
+			't': {{'t'}, {'7'}},
+			'z': {{'2'}, {'2'}},
+
+			'A': {{'4'}, {'@'}},
+			'B': {{'8'}, {'8'}},
+			'E': {{'3'}, {'3'}},
+			'G': {{'9'}, {'9'}},
+			'I': {{'1'}, {'!'}},
+			'L': {{'L'}, {'1'}},
+			'O': {{'0'}, {'0'}},
+			'S': {{'5'}, {'$'}},
+			'T': {{'T'}, {'7'}},
+			'Z': {{'2'}, {'2'}},
+		},
+		MaxConfidence: 1,
+	},
+	Sequences: map[string][][]rune{
+		"ck": {{'x'}, {'x'}},
+		"ks": {{'x'}, {'x'}},
+		"ph": {{'f'}, {'f'}},
+		"CK": {{'X'}, {'X'}},
+		"KS": {{'X'}, {'X'}},
+		"PH": {{'F'}, {'F'}},
+	},
+}
+
+// LeetIntensity controls how many characters the Leetspeak transformer substitutes.
+type LeetIntensity int
+
+const (
+	// LeetOne substitutes one character at a time, from left to right: elite -> 3lite.
+	LeetOne LeetIntensity = iota
+	// LeetAlternate substitutes every other character, starting with the first: elite ->
+	// 3lit3.
+	LeetAlternate
+	// LeetAll substitutes all the characters: elite -> 3l1t3.
+	LeetAll
+)
+
+func (i LeetIntensity) String() string {
+	switch i {
+	case LeetOne:
+		return "One"
+	case LeetAlternate:
+		return "Alternate"
+	case LeetAll:
+		return "All"
+	default:
+		return "LeetIntensity(" + strconv.Itoa(int(i)) + ")"
+	}
+}
+
+// Leetspeak alters the string by replacing runes and sequences of runes with their
+// leetspeak substitutions.
+//
+// The substitutions are provided by the leet maps, the algorithm exhausts each map
+// sequentially, confidence level by confidence level. The intensity controls which of the
+// substitutable characters are replaced, substitutions which would make the string longer
+// than MaxBytes are skipped.
+var Leetspeak = func(intensity LeetIntensity, maps ...LeetMap) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		var maxSeq int
+		for _, m := range maps {
+			for seq := range m.Sequences {
+				if len(seq) > maxSeq {
+					maxSeq = len(seq)
+				}
+			}
+		}
+
+		return &leetTransformer{
+			cfg:       cfg,
+			intensity: intensity,
+			maps:      maps,
+			maxSeq:    maxSeq,
+		}, false
+	}
+}
+
+type leetTransformer struct {
+	cfg       *Config
+	intensity LeetIntensity
+	maps      []LeetMap
+	// maxSeq is the length in bytes of the longest sequence.
+	maxSeq int
+}
+
+// leetSite is a substitutable part of the string.
+type leetSite struct {
+	start, end int
+	sub        []rune
+}
+
+func (t *leetTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Leetspeak",
+		Params: []Param{
+			{"intensity", t.intensity.String()},
+			{"maps", strconv.Itoa(len(t.maps))},
+		},
+	}
+}
+
+func (t *leetTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	for _, m := range t.maps {
+		for confidence := 0; confidence <= m.MaxConfidence; confidence++ {
+			sites := t.sites(in.Message, m, confidence)
+			if len(sites) == 0 {
+				continue
+			}
+
+			for _, pick := range t.picks(len(sites)) {
+				select {
+				case <-ctx.Done():
+					return in, ctx.Err()
+				default:
+				}
+
+				out := t.build(in.Message, sites, pick)
+				if out == in.Message {
+					continue
+				}
+
+				ok, err := t.cfg.Source.Valid(ctx, out)
+				if err != nil || ok {
+					in.setAndIncrement(out)
+					return in, err
+				}
+			}
+		}
+	}
+
+	return in, nil
+}
+
+// picks returns the selections of sites to substitute, in the order they are tried.
+func (t *leetTransformer) picks(n int) []func(i int) bool {
+	switch t.intensity {
+	case LeetOne:
+		picks := make([]func(i int) bool, n)
+		for j := range picks {
+			j := j
+			picks[j] = func(i int) bool { return i == j }
+		}
+		return picks
+
+	case LeetAlternate:
+		picks := []func(i int) bool{
+			func(i int) bool { return i%2 == 0 },
+		}
+		if n > 1 {
+			picks = append(picks, func(i int) bool { return i%2 == 1 })
+		}
+		return picks
+
+	default:
+		return []func(i int) bool{
+			func(int) bool { return true },
+		}
+	}
+}
+
+// sites finds the substitutable parts of s at the confidence level of the map. Sequences
+// are matched greedily, the longest sequence first.
+func (t *leetTransformer) sites(s string, m LeetMap, confidence int) []leetSite {
+	var sites []leetSite
+	for i := 0; i < len(s); {
+		site, ok := t.match(s, i, m, confidence)
+		if ok {
+			sites = append(sites, site)
+			i = site.end
+			continue
+		}
+
+		_, width := utf8.DecodeRuneInString(s[i:])
+		i += width
+	}
+
+	return sites
+}
+
+// match matches a sequence or a rune at the start of s[i:].
+func (t *leetTransformer) match(s string, i int, m LeetMap, confidence int) (leetSite, bool) {
+	for l := t.maxSeq; l > 1; l-- {
+		if i+l > len(s) {
+			continue
+		}
+
+		seq := s[i : i+l]
+		if sub, ok := leetSub(m.Sequences[seq], confidence); ok && string(sub) != seq {
+			return leetSite{i, i + l, sub}, true
+		}
+	}
+
+	c, width := utf8.DecodeRuneInString(s[i:])
+	if c == utf8.RuneError && width == 1 {
+		return leetSite{}, false
+	}
+	if sub, ok := leetSub(m.Map[c], confidence); ok && (len(sub) != 1 || sub[0] != c) {
+		return leetSite{i, i + width, sub}, true
+	}
+
+	return leetSite{}, false
+}
+
+// leetSub gets the substitution at the confidence level.
+func leetSub(subs [][]rune, confidence int) ([]rune, bool) {
+	if len(subs) <= confidence {
+		return nil, false
+	}
+
+	return subs[confidence], true
+}
+
+// build substitutes the picked sites of s.
+//
+// build makes sure that the MaxBytes field is followed, a substitution which doesent fit
+// is skipped and the original value is kept.
+func (t *leetTransformer) build(s string, sites []leetSite, pick func(i int) bool) string {
+	var b strings.Builder
+	b.Grow(len(s) + utf8.UTFMax)
+
+	var last int
+	for i, site := range sites {
+		if !pick(i) {
+			continue
+		}
+
+		var width int
+		for _, r := range site.sub {
+			width += utf8.RuneLen(r)
+		}
+		// the prefix, the substitution and the remaining string must fit.
+		if t.cfg.MaxBytes > 0 && b.Len()+len(s[last:site.start])+width+len(s[site.end:]) > t.cfg.MaxBytes {
+			continue
+		}
+
+		b.WriteString(s[last:site.start])
+		for _, r := range site.sub {
+			b.WriteRune(r)
+		}
+		last = site.end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package sinoname
+
+import "testing"
+
+func TestLeetspeak(t *testing.T) {
+	t.Run("One", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Leetspeak(LeetOne, ASCIILeet)(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "elite", "3lite", "el1te")
+	})
+
+	t.Run("Alternate_Odd", func(t *testing.T) {
+		src := newStaticSource("3lit3")
+		tr, _ := Leetspeak(LeetAlternate, ASCIILeet)(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "elite", "el1te")
+	})
+
+	t.Run("Next_Confidence", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Leetspeak(LeetAll, ASCIILeet)(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "leet", "l33t", "1337", "leet")
+	})
+
+	t.Run("Max_Bytes", func(t *testing.T) {
+		src := newStaticSource()
+		leet := LeetMap{Sequences: map[string][][]rune{"ck": {{'|', '<', '<'}}}}
+		tr, _ := Leetspeak(LeetAll, leet)(&Config{MaxBytes: 6, Source: src})
+
+		transformSequence(t, tr, src, "ckckc", "|<<ckc")
+	})
+}
@@ -0,0 +1,238 @@
+package sinoname
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NicknameDictionary maps lower case given names to their nicknames and diminutives.
+type NicknameDictionary map[string][]string
+
+// EnglishNicknames holds common english given names and their nicknames.
+var EnglishNicknames NicknameDictionary = NicknameDictionary{
+	"abigail":     {"abby", "gail"},
+	"alexander":   {"alex", "xander", "sasha"},
+	"alexandra":   {"alex", "lexi", "sandra"},
+	"andrew":      {"andy", "drew"},
+	"anthony":     {"tony", "ant"},
+	"barbara":     {"barb", "babs"},
+	"benjamin":    {"ben", "benny", "benji"},
+	"catherine":   {"cathy", "kate", "katie"},
+	"charles":     {"charlie", "chuck", "chas"},
+	"charlotte":   {"lottie", "charlie"},
+	"christopher": {"chris", "kit", "topher"},
+	"daniel":      {"dan", "danny"},
+	"david":       {"dave", "davey"},
+	"deborah":     {"deb", "debbie"},
+	"dorothy":     {"dot", "dottie"},
+	"edward":      {"ed", "eddie", "ted", "ned"},
+	"elizabeth":   {"liz", "beth", "lizzy", "betty", "eliza"},
+	"frederick":   {"fred", "freddie"},
+	"gabriel":     {"gabe"},
+	"gregory":     {"greg"},
+	"henry":       {"harry", "hank", "hal"},
+	"jacob":       {"jake"},
+	"james":       {"jim", "jimmy", "jamie"},
+	"jennifer":    {"jen", "jenny"},
+	"jessica":     {"jess", "jessie"},
+	"john":        {"jack", "johnny"},
+	"jonathan":    {"jon", "jonny"},
+	"joseph":      {"joe", "joey"},
+	"joshua":      {"josh"},
+	"katherine":   {"kate", "kathy", "katie", "kat"},
+	"kenneth":     {"ken", "kenny"},
+	"lawrence":    {"larry"},
+	"leonard":     {"leo", "len", "lenny"},
+	"margaret":    {"maggie", "meg", "peggy", "marge"},
+	"matthew":     {"matt"},
+	"michael":     {"mike", "mikey", "mick"},
+	"nicholas":    {"nick", "nicky"},
+	"patricia":    {"pat", "patty", "trish"},
+	"patrick":     {"pat", "paddy"},
+	"peter":       {"pete"},
+	"rebecca":     {"becky", "becca"},
+	"richard":     {"rick", "dick", "rich", "richie"},
+	"robert":      {"bob", "rob", "bobby", "robbie"},
+	"ronald":      {"ron", "ronnie"},
+	"samantha":    {"sam", "sammy"},
+	"samuel":      {"sam", "sammy"},
+	"stephen":     {"steve", "stevie"},
+	"steven":      {"steve", "stevie"},
+	"susan":       {"sue", "susie"},
+	"theodore":    {"ted", "teddy", "theo"},
+	"thomas":      {"tom", "tommy"},
+	"timothy":     {"tim", "timmy"},
+	"victoria":    {"vicky", "tori"},
+	"william":     {"bill", "will", "billy", "willy", "liam"},
+	"zachary":     {"zach", "zack"},
+}
+
+// LoadNicknamesCSV reads a nickname dictionary from CSV records made of a given name
+// followed by its nicknames, lines starting with # are ignored:
+//
+//	william,bill,will
+//	robert,bob,rob
+func LoadNicknamesCSV(r io.Reader) (NicknameDictionary, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	dict := make(NicknameDictionary)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return dict, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := cr.FieldPos(0)
+		if len(record) < 2 {
+			return nil, fmt.Errorf("sinoname: nickname record on line %d has no nicknames", line)
+		}
+
+		name := strings.ToLower(strings.TrimSpace(record[0]))
+		for _, nick := range record[1:] {
+			dict.add(name, strings.ToLower(strings.TrimSpace(nick)))
+		}
+	}
+}
+
+// Bidirectional returns a copy of the dictionary which also maps the nicknames to their
+// given names (bill -> william).
+func (d NicknameDictionary) Bidirectional() NicknameDictionary {
+	out := make(NicknameDictionary, len(d))
+	for name, nicks := range d {
+		for _, nick := range nicks {
+			out.add(name, nick)
+			out.add(nick, name)
+		}
+	}
+
+	return out
+}
+
+// add adds the nickname to the name, ignoring duplicates.
+func (d NicknameDictionary) add(name, nick string) {
+	if name == "" || nick == "" || name == nick {
+		return
+	}
+
+	for _, v := range d[name] {
+		if v == nick {
+			return
+		}
+	}
+	d[name] = append(d[name], nick)
+}
+
+// Nickname alters the string by replacing a given name with one of its nicknames from the
+// dictionary: William.Smith -> Bill.Smith, Will.Smith .
+//
+// The string is split in tokens via the Tokenize config field, each token is replaced
+// in place keeping the separators of the string and the casing of the token.
+var Nickname = func(dict NicknameDictionary) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		return &nicknameTransformer{
+			cfg:  cfg,
+			dict: dict,
+		}, false
+	}
+}
+
+type nicknameTransformer struct {
+	cfg  *Config
+	dict NicknameDictionary
+}
+
+func (t *nicknameTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Nickname",
+		Params: []Param{
+			{"names", strconv.Itoa(len(t.dict))},
+		},
+	}
+}
+
+// Estimate returns the number of nicknames of the tokens which fit in MaxBytes.
+func (t *nicknameTransformer) Estimate(_ context.Context, in MessagePacket) int {
+	var n int
+	t.each(in.Message, func(string) bool {
+		n++
+		return false
+	})
+
+	return n
+}
+
+func (t *nicknameTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	var err error
+	t.each(in.Message, func(out string) bool {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return true
+		default:
+		}
+
+		var ok bool
+		ok, err = t.cfg.Source.Valid(ctx, out)
+		if err != nil || ok {
+			in.setAndIncrement(out)
+			return true
+		}
+		return false
+	})
+
+	return in, err
+}
+
+// each calls f with each variant of s, from the first token to the last, till f returns
+// true.
+func (t *nicknameTransformer) each(s string, f func(string) bool) {
+	var offset int
+	for _, token := range t.cfg.Tokenize(s) {
+		i := strings.Index(s[offset:], token)
+		// the token isnt part of the string (custom Tokenize).
+		if i < 0 || token == "" {
+			continue
+		}
+		start := offset + i
+		offset = start + len(token)
+
+		for _, nick := range t.dict[strings.ToLower(token)] {
+			nick = matchTokenCase(token, nick)
+			if t.cfg.MaxBytes > 0 && len(s)-len(token)+len(nick) > t.cfg.MaxBytes {
+				continue
+			}
+
+			if f(s[:start] + nick + s[offset:]) {
+				return
+			}
+		}
+	}
+}
+
+// matchTokenCase returns v with the casing style of token: upper case, title case or
+// lower case.
+func matchTokenCase(token, v string) string {
+	first, _ := utf8.DecodeRuneInString(token)
+	switch {
+	case !unicode.IsUpper(first):
+		return v
+	case strings.ToUpper(token) == token && utf8.RuneCountInString(token) > 1:
+		return strings.ToUpper(v)
+	default:
+		r, width := utf8.DecodeRuneInString(v)
+		return string(unicode.ToUpper(r)) + v[width:]
+	}
+}
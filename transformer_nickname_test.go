@@ -0,0 +1,53 @@
+package sinoname
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNickname(t *testing.T) {
+	t.Run("Next_Nickname", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Nickname(EnglishNicknames)(&Config{MaxBytes: 100, Source: src, Tokenize: tokenizeDefault})
+
+		transformSequence(t, tr, src, "William.Smith", "Bill.Smith", "Will.Smith")
+	})
+
+	t.Run("Max_Bytes", func(t *testing.T) {
+		tr, _ := Nickname(NicknameDictionary{"al": {"alphonse", "alf"}})(&Config{
+			MaxBytes: 5,
+			Source:   noopSource{true},
+			Tokenize: tokenizeDefault,
+		})
+
+		out, err := tr.Transform(context.Background(), MessagePacket{Message: "Al.B"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Message != "Alf.B" {
+			t.Fatalf("expected Alf.B got %v", out.Message)
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		dict, err := LoadNicknamesCSV(strings.NewReader("# name,nicknames\nWilliam, Bill, will\nrobert,bob\nwilliam,liam,bill\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[string][]string{"william": {"bill", "will", "liam"}, "robert": {"bob"}}
+		if len(dict) != len(want) {
+			t.Fatalf("expected %v got %v", want, dict)
+		}
+		for name, nicks := range want {
+			if strings.Join(dict[name], ",") != strings.Join(nicks, ",") {
+				t.Fatalf("expected %v got %v", want, dict)
+			}
+		}
+
+		if _, err := LoadNicknamesCSV(strings.NewReader("william,bill\nrobert\n")); err == nil {
+			t.Fatal("expected error for a record without nicknames")
+		}
+	})
+}
@@ -11,6 +11,10 @@ var Noop = func(_ *Config) (Transformer, bool) {
 
 type noopTransformer struct{}
 
+func (_ *noopTransformer) Describe() TransformerDescription {
+	return TransformerDescription{Name: "Noop"}
+}
+
 func (t *noopTransformer) Transform(_ context.Context, in MessagePacket) (MessagePacket, error) {
 	return in, nil
 }
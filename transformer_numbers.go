@@ -7,48 +7,51 @@ import (
 
 // NumbersPrefix adds a integer to the beginning of the string.
 // It obtains the integer by:
-//  1. From the context via NumberFromContext .
+//  1. From the context via the hints, in order (NumberFromContext if no hints are provided).
 //  2. Collects all the numbers from the string.
 //
 // Foo1 Bar2 Buz3 -> 123Foo Bar Buz .
-var NumbersPrefix = func(sep string) func(cfg *Config) (Transformer, bool) {
+var NumbersPrefix = func(sep string, hints ...NumberHint) func(cfg *Config) (Transformer, bool) {
 	return func(cfg *Config) (Transformer, bool) {
 		return &numbersTransformer{
 			where: prefix,
 			cfg:   cfg,
 			sep:   sep,
+			hints: numberHints(hints),
 		}, false
 	}
 }
 
 // NumbersSuffix adds a integer to the end of the string.
 // It obtains the integer by:
-//  1. From the context via NumberFromContext .
+//  1. From the context via the hints, in order (NumberFromContext if no hints are provided).
 //  2. Collects all the numbers from the string.
 //
 // Foo1 Bar2 Buz3 -> Foo Bar Buz123
-var NumbersSuffix = func(sep string) func(cfg *Config) (Transformer, bool) {
+var NumbersSuffix = func(sep string, hints ...NumberHint) func(cfg *Config) (Transformer, bool) {
 	return func(cfg *Config) (Transformer, bool) {
 		return &numbersTransformer{
 			where: suffix,
 			cfg:   cfg,
 			sep:   sep,
+			hints: numberHints(hints),
 		}, false
 	}
 }
 
 // NumbersCircumfix adds a integer to the end and beinning of the string.
 // It obtains the integer by:
-//  1. From the context via NumberFromContext .
+//  1. From the context via the hints, in order (NumberFromContext if no hints are provided).
 //  2. Collects all the numbers from the string.
 //
 // Foo1 Bar2 Buz3 -> 123Foo Bar Buz123
-var NumbersCircumfix = func(sep string) func(cfg *Config) (Transformer, bool) {
+var NumbersCircumfix = func(sep string, hints ...NumberHint) func(cfg *Config) (Transformer, bool) {
 	return func(cfg *Config) (Transformer, bool) {
 		return &numbersTransformer{
 			where: circumfix,
 			cfg:   cfg,
 			sep:   sep,
+			hints: numberHints(hints),
 		}, false
 	}
 }
@@ -57,6 +60,44 @@ type numbersTransformer struct {
 	where affix
 	cfg   *Config
 	sep   string
+	hints []NumberHint
+}
+
+// numberHints returns the hints used by default if none are provided.
+func numberHints(hints []NumberHint) []NumberHint {
+	if len(hints) == 0 {
+		return []NumberHint{HintNumber}
+	}
+	return hints
+}
+
+func (t *numbersTransformer) Describe() TransformerDescription {
+	params := []Param{
+		{"sep", strconv.Quote(t.sep)},
+	}
+	if len(t.hints) != 1 || t.hints[0] != HintNumber {
+		params = append(params, Param{"hints", joinHints(t.hints)})
+	}
+
+	return TransformerDescription{
+		Name:   "Numbers" + t.where.String(),
+		Params: params,
+	}
+}
+
+// Estimate returns 1 plus the number of hinted numbers which fit in MaxBytes.
+func (t *numbersTransformer) Estimate(ctx context.Context, in MessagePacket) int {
+	if len(in.Message)+len(t.sep) > t.cfg.MaxBytes {
+		return 0
+	}
+
+	n := 1
+	for _, num := range hintNumbers(ctx, t.cfg, t.hints) {
+		if _, ok := applyAffix(t.cfg, t.where, in.Message, t.sep, num); ok {
+			n++
+		}
+	}
+	return n
 }
 
 func (t *numbersTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
@@ -64,15 +105,16 @@ func (t *numbersTransformer) Transform(ctx context.Context, in MessagePacket) (M
 		return in, nil
 	}
 
-	if v, ok := NumberFromContext(ctx); ok {
-		num := strconv.Itoa(v)
+	for _, num := range hintNumbers(ctx, t.cfg, t.hints) {
 		out, ok := applyAffix(t.cfg, t.where, in.Message, t.sep, num)
-		if ok {
-			unique, err := t.cfg.Source.Valid(ctx, out)
-			if err != nil || unique {
-				in.setAndIncrement(out)
-				return in, err
-			}
+		if !ok {
+			continue
+		}
+
+		unique, err := t.cfg.Source.Valid(ctx, out)
+		if err != nil || unique {
+			in.setAndIncrement(out)
+			return in, err
 		}
 	}
 
@@ -18,6 +18,10 @@ type pascalCaseTransformer struct {
 	cfg *Config
 }
 
+func (_ *pascalCaseTransformer) Describe() TransformerDescription {
+	return TransformerDescription{Name: "PascalCase"}
+}
+
 func (t *pascalCaseTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	split := t.cfg.Tokenize(in.Message)
 	for i, word := range split {
@@ -0,0 +1,252 @@
+package sinoname
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// PhoneticRule rewrites a part of a word to a part which sounds alike (ph -> f). Rules
+// match ascii letters case insensitively.
+type PhoneticRule struct {
+	From, To string
+	// AfterVowel restricts the rule to the matches preceded by a vowel (Phil -> Phill but
+	// not Phil -> Pphil).
+	AfterVowel bool
+}
+
+// DefaultPhoneticRules are the rules used by Phonetic if none are provided: ph <-> f,
+// ch <-> k, c <-> k, y <-> i, a <-> ay, s <-> z and doubled consonants (l <-> ll, ...).
+var DefaultPhoneticRules = defaultPhoneticRules()
+
+func defaultPhoneticRules() []PhoneticRule {
+	rules := []PhoneticRule{
+		{From: "ph", To: "f"}, {From: "f", To: "ph"},
+		{From: "ch", To: "k"}, {From: "k", To: "ch"},
+		{From: "c", To: "k"}, {From: "k", To: "c"},
+		{From: "ck", To: "k"}, {From: "ck", To: "x"},
+		{From: "y", To: "i"}, {From: "i", To: "y"},
+		{From: "a", To: "ay"}, {From: "ay", To: "a"},
+		{From: "s", To: "z"}, {From: "z", To: "s"},
+		{From: "ee", To: "ea"}, {From: "ea", To: "ee"},
+	}
+
+	for _, c := range "bdfglmnprt" {
+		single, double := string(c), string([]rune{c, c})
+		rules = append(rules,
+			PhoneticRule{From: double, To: single},
+			PhoneticRule{From: single, To: double, AfterVowel: true},
+		)
+	}
+
+	return rules
+}
+
+// Phonetic alters the string by spelling it differently while keeping the way it sounds:
+// Christopher -> Kristopher, Jason -> Jayson, Phil -> Fil.
+//
+// The variants are generated by applying one rule at a time, only the variants with the
+// same phonetic key (the soundex code of each word, the first letters only need to sound
+// alike) as the original string are tried against the source. The variants with the exact
+// same soundex codes are tried first, in the order of the rules.
+var Phonetic = func(rules ...PhoneticRule) func(cfg *Config) (Transformer, bool) {
+	if len(rules) == 0 {
+		rules = DefaultPhoneticRules
+	}
+
+	return func(cfg *Config) (Transformer, bool) {
+		return &phoneticTransformer{
+			cfg:   cfg,
+			rules: rules,
+		}, false
+	}
+}
+
+type phoneticTransformer struct {
+	cfg   *Config
+	rules []PhoneticRule
+}
+
+func (t *phoneticTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Phonetic",
+		Params: []Param{
+			{"rules", strconv.Itoa(len(t.rules))},
+		},
+	}
+}
+
+func (t *phoneticTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	for _, out := range t.variants(in.Message) {
+		select {
+		case <-ctx.Done():
+			return in, ctx.Err()
+		default:
+		}
+
+		ok, err := t.cfg.Source.Valid(ctx, out)
+		if err != nil || ok {
+			in.setAndIncrement(out)
+			return in, err
+		}
+	}
+
+	return in, nil
+}
+
+// variants returns the ranked variants of s with the same phonetic key as s.
+func (t *phoneticTransformer) variants(s string) []string {
+	code := soundexWords(s)
+	key := phoneticKey(code)
+	if key == "" {
+		return nil
+	}
+
+	lower := lowerASCII(s)
+	seen := map[string]struct{}{s: {}}
+	var exact, alike []string
+	for _, rule := range t.rules {
+		from := lowerASCII(rule.From)
+		if from == "" {
+			continue
+		}
+
+		for i := strings.Index(lower, from); i >= 0; {
+			start := i
+			if next := strings.Index(lower[i+1:], from); next >= 0 {
+				i += next + 1
+			} else {
+				i = -1
+			}
+
+			if rule.AfterVowel && (start == 0 || !strings.ContainsRune("aeiouy", rune(lower[start-1]))) {
+				continue
+			}
+
+			v := s[:start] + matchCase(s[start:start+len(from)], rule.To) + s[start+len(from):]
+			if hasTriple(v) {
+				continue
+			}
+			if _, ok := seen[v]; ok || (t.cfg.MaxBytes > 0 && len(v) > t.cfg.MaxBytes) {
+				continue
+			}
+			seen[v] = struct{}{}
+
+			switch vCode := soundexWords(v); {
+			case vCode == code:
+				exact = append(exact, v)
+			case phoneticKey(vCode) == key:
+				alike = append(alike, v)
+			}
+		}
+	}
+
+	return append(exact, alike...)
+}
+
+// soundexCodes holds the soundex code of each letter, 0 for vowels and h for the letters
+// ignored by soundex (h, w).
+const soundexCodes = "0123012h02245501262301h202"
+
+// Soundex returns the american soundex code of the ascii letters of s (Robert -> R163),
+// "" if s has no ascii letters.
+func Soundex(s string) string {
+	out := make([]byte, 0, 4)
+	var last byte
+	for i := 0; i < len(s) && len(out) < 4; i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			continue
+		}
+
+		code := soundexCodes[c-'a']
+		if len(out) == 0 {
+			out = append(out, c&^0x20)
+			if code != 'h' {
+				last = code
+			}
+			continue
+		}
+
+		switch code {
+		case 'h': // h and w dont separate letters with the same code.
+		case '0':
+			last = 0
+		default:
+			if code != last {
+				out = append(out, code)
+			}
+			last = code
+		}
+	}
+
+	if len(out) == 0 {
+		return ""
+	}
+	for len(out) < 4 {
+		out = append(out, '0')
+	}
+	return string(out)
+}
+
+// soundexWords returns the soundex codes of the words of s, separated by spaces.
+func soundexWords(s string) string {
+	var codes []string
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z')
+	}) {
+		codes = append(codes, Soundex(w))
+	}
+
+	return strings.Join(codes, " ")
+}
+
+// phoneticKey replaces the first letter of each soundex code with its code so that words
+// starting with letters which sound alike (Christopher, Kristopher) share the key.
+func phoneticKey(codes string) string {
+	b := []byte(codes)
+	for i := range b {
+		if i == 0 || b[i-1] == ' ' {
+			code := soundexCodes[(b[i]|0x20)-'a']
+			if code == 'h' {
+				code = '0'
+			}
+			b[i] = code
+		}
+	}
+
+	return string(b)
+}
+
+// hasTriple reports whether s has the same letter 3 times in a row (Phill -> Philll).
+func hasTriple(s string) bool {
+	s = lowerASCII(s)
+	for i := 2; i < len(s); i++ {
+		if s[i] == s[i-1] && s[i] == s[i-2] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lowerASCII lowers the ascii letters of s, keeping the length of s.
+func lowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c | 0x20
+		}
+	}
+
+	return string(b)
+}
+
+// matchCase capitalizes the first letter of to if orig starts with an upper case letter.
+func matchCase(orig, to string) string {
+	if to == "" || orig[0] < 'A' || orig[0] > 'Z' {
+		return to
+	}
+
+	return strings.ToUpper(to[:1]) + to[1:]
+}
@@ -0,0 +1,57 @@
+package sinoname
+
+import "testing"
+
+func TestSoundex(t *testing.T) {
+	for in, want := range map[string]string{
+		"Robert":   "R163",
+		"Rupert":   "R163",
+		"Ashcraft": "A261",
+		"Tymczak":  "T522",
+		"Pfister":  "P236",
+		"Lee":      "L000",
+		"123":      "",
+	} {
+		if got := Soundex(in); got != want {
+			t.Fatalf("%v: expected %v got %v", in, want, got)
+		}
+	}
+}
+
+func TestPhonetic(t *testing.T) {
+	t.Run("Next_Variant", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Phonetic()(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "Jason", "Jayson", "Jazon")
+		transformSequence(t, tr, src, "Phil", "Phyl", "Phill", "Fil", "Phil")
+	})
+
+	t.Run("Ranking", func(t *testing.T) {
+		tr, _ := Phonetic()(&Config{MaxBytes: 100, Source: noopSource{true}})
+		variants := tr.(*phoneticTransformer).variants("Christopher")
+
+		index := make(map[string]int)
+		for i, v := range variants {
+			index[v] = i
+		}
+		exact, ok1 := index["Christofer"]
+		alike, ok2 := index["Kristopher"]
+		if !ok1 || !ok2 || exact > alike {
+			t.Fatalf("expected Christofer before Kristopher got %v", variants)
+		}
+		if _, ok := index["Christtopher"]; ok {
+			t.Fatalf("unexpected doubled consonant after a consonant in %v", variants)
+		}
+	})
+
+	t.Run("Same_Key", func(t *testing.T) {
+		tr, _ := Phonetic()(&Config{MaxBytes: 100, Source: noopSource{true}})
+		key := phoneticKey(soundexWords("Jackson"))
+		for _, v := range tr.(*phoneticTransformer).variants("Jackson") {
+			if got := phoneticKey(soundexWords(v)); got != key {
+				t.Fatalf("%v: expected key %v got %v", v, key, got)
+			}
+		}
+	})
+}
@@ -15,6 +15,10 @@ type pluralTransformer struct {
 	cfg *Config
 }
 
+func (_ *pluralTransformer) Describe() TransformerDescription {
+	return TransformerDescription{Name: "Plural"}
+}
+
 func (t *pluralTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	if len(in.Message)+1 > t.cfg.MaxBytes {
 		return in, nil
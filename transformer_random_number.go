@@ -0,0 +1,167 @@
+package sinoname
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Lambels/sinoname/rng"
+)
+
+// UglyNumbers are numbers commonly unwanted in usernames, use them as the deny list of
+// RandomSuffix.
+var UglyNumbers = []int{69, 420, 666, 1488}
+
+// RandomSuffix adds a random number of minDigits to maxDigits digits (1 <= digits <= 9)
+// to the end of the string: Foo -> Foo_42, Foo_7315 .
+//
+// The numbers are drawn at random without repeats from the RandSrc config field (see
+// rng.AffineGen), all the numbers of a length are tried before moving to the next length,
+// the shortest first. If pad is true the numbers are zero padded (Foo_07) else the numbers
+// of n digits are the ones without leading zeros.
+//
+// The numbers containing any of the denied numbers are never added:
+//
+//	sinoname.RandomSuffix("_", 2, 4, false, sinoname.UglyNumbers...)
+//
+// Unlike IncrementalSuffix, the added number doesnt reveal how many values were taken.
+// Like IncrementalSuffix, a call makes one source call per number when all of them are
+// taken, keep maxDigits small or bound the call via the context or Timeout.
+var RandomSuffix = func(sep string, minDigits, maxDigits int, pad bool, deny ...int) func(cfg *Config) (Transformer, bool) {
+	minDigits = clampInt(minDigits, 1, 9)
+	maxDigits = clampInt(maxDigits, minDigits, 9)
+
+	denied := make([]string, len(deny))
+	for i, d := range deny {
+		denied[i] = strconv.Itoa(d)
+	}
+
+	return func(cfg *Config) (Transformer, bool) {
+		return &randomNumberTransformer{
+			cfg:    cfg,
+			sep:    sep,
+			min:    minDigits,
+			max:    maxDigits,
+			pad:    pad,
+			denied: denied,
+		}, false
+	}
+}
+
+type randomNumberTransformer struct {
+	cfg      *Config
+	sep      string
+	min, max int
+	pad      bool
+	denied   []string
+}
+
+func (t *randomNumberTransformer) Describe() TransformerDescription {
+	params := []Param{
+		{"sep", strconv.Quote(t.sep)},
+		{"digits", strconv.Itoa(t.min) + "-" + strconv.Itoa(t.max)},
+	}
+	if t.pad {
+		params = append(params, Param{"pad", "true"})
+	}
+	if len(t.denied) > 0 {
+		params = append(params, Param{"deny", strings.Join(t.denied, ",")})
+	}
+
+	return TransformerDescription{
+		Name:   "RandomSuffix",
+		Params: params,
+	}
+}
+
+// Estimate returns the number of numbers which fit in MaxBytes, the denied numbers
+// included.
+func (t *randomNumberTransformer) Estimate(_ context.Context, in MessagePacket) int {
+	var n int
+	for digits := t.min; digits <= t.max; digits++ {
+		if _, ok := applyAffix(t.cfg, suffix, in.Message, t.sep, strings.Repeat("0", digits)); !ok {
+			break
+		}
+
+		_, count := t.digitRange(digits)
+		n = addSat(n, count)
+	}
+
+	return n
+}
+
+func (t *randomNumberTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	for digits := t.min; digits <= t.max; digits++ {
+		// longer numbers wont fit either.
+		if _, ok := applyAffix(t.cfg, suffix, in.Message, t.sep, strings.Repeat("0", digits)); !ok {
+			return in, nil
+		}
+
+		lo, count := t.digitRange(digits)
+		gen := rng.NewAffineGen(t.cfg.intn(math.MaxInt), count)
+		for done := false; !done; {
+			select {
+			case <-ctx.Done():
+				return in, ctx.Err()
+			default:
+			}
+
+			var i int
+			i, done = gen.Next()
+
+			v := strconv.Itoa(lo + i)
+			if t.isDenied(v) {
+				continue
+			}
+			if t.pad {
+				v = strings.Repeat("0", digits-len(v)) + v
+			}
+
+			out, _ := applyAffix(t.cfg, suffix, in.Message, t.sep, v)
+			ok, err := t.cfg.Source.Valid(ctx, out)
+			if err != nil || ok {
+				in.setAndIncrement(out)
+				return in, err
+			}
+		}
+	}
+
+	return in, nil
+}
+
+// digitRange returns the smallest number of the given number of digits and how many
+// numbers have this number of digits.
+func (t *randomNumberTransformer) digitRange(digits int) (int, int) {
+	count := 1
+	for i := 0; i < digits; i++ {
+		count *= 10
+	}
+
+	if t.pad || digits == 1 {
+		return 0, count
+	}
+	return count / 10, count - count/10
+}
+
+// isDenied reports whether v contains any of the denied numbers.
+func (t *randomNumberTransformer) isDenied(v string) bool {
+	for _, d := range t.denied {
+		if strings.Contains(v, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clampInt returns v bounded to [min, max].
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,150 @@
+package sinoname
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// collectRandomSuffixes runs the transformer on in till it returns in, adding each value
+// to the source.
+func collectRandomSuffixes(t *testing.T, src *staticSrc, tr Transformer, in string) []string {
+	t.Helper()
+
+	var out []string
+	seen := make(map[string]bool)
+	for {
+		msg, err := tr.Transform(context.Background(), MessagePacket{Message: in})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Message == in {
+			return out
+		}
+		if seen[msg.Message] {
+			t.Fatalf("repeated %v", msg.Message)
+		}
+		seen[msg.Message] = true
+		out = append(out, msg.Message)
+		src.addValue(msg.Message)
+	}
+}
+
+func TestRandomSuffix(t *testing.T) {
+	t.Run("Digits", func(t *testing.T) {
+		src := newStaticSource()
+		cfg := &Config{MaxBytes: 100, Source: src}
+		tr, _ := RandomSuffix("_", 2, 2, false, UglyNumbers...)(cfg)
+
+		out := collectRandomSuffixes(t, src, tr, "foo")
+		// 90 numbers of 2 digits without 69.
+		if len(out) != 89 {
+			t.Fatalf("expected 89 values got %v", len(out))
+		}
+		for _, v := range out {
+			n, err := strconv.Atoi(strings.TrimPrefix(v, "foo_"))
+			if err != nil || n < 10 || n > 99 || n == 69 {
+				t.Fatalf("unexpected value %v", v)
+			}
+		}
+	})
+
+	t.Run("Pad", func(t *testing.T) {
+		src := newStaticSource()
+		cfg := &Config{MaxBytes: 100, Source: src}
+		tr, _ := RandomSuffix("", 2, 2, true)(cfg)
+
+		out := collectRandomSuffixes(t, src, tr, "foo")
+		if len(out) != 100 {
+			t.Fatalf("expected 100 values got %v", len(out))
+		}
+		for _, v := range out {
+			if len(v) != 5 {
+				t.Fatalf("unexpected value %v", v)
+			}
+		}
+	})
+
+	t.Run("Deny_Contains", func(t *testing.T) {
+		src := newStaticSource()
+		cfg := &Config{MaxBytes: 100, Source: src}
+		tr, _ := RandomSuffix("", 3, 3, false, 69)(cfg)
+
+		out := collectRandomSuffixes(t, src, tr, "foo")
+		// 900 numbers of 3 digits without 69x and x69.
+		if len(out) != 900-10-9 {
+			t.Fatalf("expected %v values got %v", 900-10-9, len(out))
+		}
+		for _, v := range out {
+			if strings.Contains(v, "69") {
+				t.Fatalf("unexpected value %v", v)
+			}
+		}
+	})
+
+	t.Run("Shortest_First", func(t *testing.T) {
+		src := newStaticSource()
+		cfg := &Config{MaxBytes: 100, Source: src}
+		tr, _ := RandomSuffix("", 1, 2, false)(cfg)
+
+		out := collectRandomSuffixes(t, src, tr, "foo")
+		if len(out) != 100 {
+			t.Fatalf("expected 100 values got %v", len(out))
+		}
+		for i, v := range out {
+			if (i < 10) != (len(v) == 4) {
+				t.Fatalf("unexpected value %v at %v", v, i)
+			}
+		}
+	})
+
+	t.Run("Max_Bytes", func(t *testing.T) {
+		src := newStaticSource()
+		cfg := &Config{MaxBytes: 5, Source: src}
+		tr, _ := RandomSuffix("_", 1, 3, false)(cfg)
+
+		out := collectRandomSuffixes(t, src, tr, "foo")
+		if len(out) != 10 {
+			t.Fatalf("expected 10 values got %v", len(out))
+		}
+		if n := tr.(*randomNumberTransformer).Estimate(context.Background(), MessagePacket{Message: "foo"}); n != 10 {
+			t.Fatalf("expected estimate 10 got %v", n)
+		}
+	})
+
+	t.Run("Estimate", func(t *testing.T) {
+		tr, _ := RandomSuffix("", 2, 4, false, UglyNumbers...)(&Config{MaxBytes: 100})
+		n := tr.(*randomNumberTransformer).Estimate(context.Background(), MessagePacket{Message: "foo"})
+		if n != 90+900+9000 {
+			t.Fatalf("expected estimate %v got %v", 90+900+9000, n)
+		}
+	})
+
+	t.Run("Clamp", func(t *testing.T) {
+		tFact := RandomSuffix("", 0, 12, false)
+		for i := 0; i < 2; i++ {
+			tr, _ := tFact(&Config{})
+			if got := describeTransformer(tr).String(); got != `RandomSuffix(sep="", digits=1-9)` {
+				t.Fatalf("unexpected description %v", got)
+			}
+		}
+	})
+
+	t.Run("Describe", func(t *testing.T) {
+		tr, _ := RandomSuffix("_", 2, 4, true, 69, 420)(&Config{})
+		got := describeTransformer(tr)
+		want := TransformerDescription{
+			Name: "RandomSuffix",
+			Params: []Param{
+				{"sep", `"_"`},
+				{"digits", "2-4"},
+				{"pad", "true"},
+				{"deny", "69,420"},
+			},
+		}
+		if got.String() != want.String() {
+			t.Fatalf("expected %v got %v", want, got)
+		}
+	})
+}
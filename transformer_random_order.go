@@ -2,6 +2,7 @@ package sinoname
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"gonum.org/v1/gonum/stat/combin"
@@ -30,6 +31,29 @@ type shuffleOrderTransformer struct {
 	sep string
 }
 
+func (t *shuffleOrderTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "ShuffleOrder",
+		Params: []Param{
+			{"sep", strconv.Quote(t.sep)},
+		},
+	}
+}
+
+// Estimate returns the number of permutations of the tokens.
+func (t *shuffleOrderTransformer) Estimate(_ context.Context, in MessagePacket) int {
+	split := t.cfg.Tokenize(in.Message)
+	if len(split)*len(t.sep)-len(t.sep) > t.cfg.MaxBytes {
+		return 0
+	}
+
+	total := 1
+	for i := 2; i <= len(split); i++ {
+		total = mulSat(total, i)
+	}
+	return total
+}
+
 func (t *shuffleOrderTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	split := t.cfg.Tokenize(in.Message)
 
@@ -0,0 +1,174 @@
+package sinoname
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// DefaultSeparators are the separators used by SeparatorSwap if none are provided.
+var DefaultSeparators = []string{".", "_", "-"}
+
+// SeparatorSwap alters the string by swapping the separators already present for the
+// other separators, the tokens between the separators are left untouched:
+//
+// john.doe_99 -> john_doe_99, john-doe-99, john_doe.99, ...
+//
+// The assignments using the same separator everywhere are tried first, followed by the
+// mixed assignments.
+var SeparatorSwap = func(seps ...string) func(cfg *Config) (Transformer, bool) {
+	if len(seps) == 0 {
+		seps = DefaultSeparators
+	}
+
+	return func(cfg *Config) (Transformer, bool) {
+		return &separatorSwapTransformer{
+			cfg:  cfg,
+			seps: seps,
+		}, false
+	}
+}
+
+type separatorSwapTransformer struct {
+	cfg  *Config
+	seps []string
+}
+
+func (t *separatorSwapTransformer) Describe() TransformerDescription {
+	quoted := make([]string, len(t.seps))
+	for i, sep := range t.seps {
+		quoted[i] = strconv.Quote(sep)
+	}
+
+	return TransformerDescription{
+		Name: "SeparatorSwap",
+		Params: []Param{
+			{"seps", strings.Join(quoted, ",")},
+		},
+	}
+}
+
+// Estimate returns the number of assignments of the separators, without the original
+// string.
+func (t *separatorSwapTransformer) Estimate(_ context.Context, in MessagePacket) int {
+	tokens, _ := t.split(in.Message)
+	if len(tokens) < 2 {
+		return 0
+	}
+
+	total := 1
+	for i := 1; i < len(tokens); i++ {
+		total = mulSat(total, len(t.seps))
+	}
+	return total - 1
+}
+
+func (t *separatorSwapTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	tokens, seps := t.split(in.Message)
+	if len(seps) == 0 {
+		return in, nil
+	}
+
+	try := func(assignment []int) (bool, error) {
+		var b strings.Builder
+		b.WriteString(tokens[0])
+		for i, sep := range assignment {
+			b.WriteString(t.seps[sep])
+			b.WriteString(tokens[i+1])
+		}
+
+		out := b.String()
+		if out == in.Message || (t.cfg.MaxBytes > 0 && len(out) > t.cfg.MaxBytes) {
+			return false, nil
+		}
+
+		ok, err := t.cfg.Source.Valid(ctx, out)
+		if err != nil || ok {
+			in.setAndIncrement(out)
+			return true, err
+		}
+		return false, nil
+	}
+
+	// uniform assignments.
+	assignment := make([]int, len(seps))
+	for sep := range t.seps {
+		for i := range assignment {
+			assignment[i] = sep
+		}
+
+		if done, err := try(assignment); done || err != nil {
+			return in, err
+		}
+	}
+	if len(seps) == 1 {
+		return in, nil
+	}
+
+	// mixed assignments.
+	for i := range assignment {
+		assignment[i] = 0
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return in, ctx.Err()
+		default:
+		}
+
+		if !uniform(assignment) {
+			if done, err := try(assignment); done || err != nil {
+				return in, err
+			}
+		}
+
+		i := len(assignment) - 1
+		for ; i >= 0; i-- {
+			assignment[i]++
+			if assignment[i] < len(t.seps) {
+				break
+			}
+			assignment[i] = 0
+		}
+		if i < 0 {
+			return in, nil
+		}
+	}
+}
+
+// split splits s on the separators, returning the tokens and the separators between
+// them. The longest separator is matched first.
+func (t *separatorSwapTransformer) split(s string) ([]string, []string) {
+	var tokens, seps []string
+	var last int
+	for i := 0; i < len(s); {
+		var match string
+		for _, sep := range t.seps {
+			if len(sep) > len(match) && strings.HasPrefix(s[i:], sep) {
+				match = sep
+			}
+		}
+		if match == "" {
+			i++
+			continue
+		}
+
+		tokens = append(tokens, s[last:i])
+		seps = append(seps, match)
+		i += len(match)
+		last = i
+	}
+	tokens = append(tokens, s[last:])
+
+	return tokens, seps
+}
+
+func uniform(assignment []int) bool {
+	for _, v := range assignment[1:] {
+		if v != assignment[0] {
+			return false
+		}
+	}
+
+	return true
+}
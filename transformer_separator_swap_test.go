@@ -0,0 +1,58 @@
+package sinoname
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSeparatorSwap(t *testing.T) {
+	t.Run("Uniform_Then_Mixed", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := SeparatorSwap()(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "john.doe_99",
+			"john.doe.99", "john_doe_99", "john-doe-99",
+			"john.doe-99", "john_doe.99",
+		)
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		src := newStaticSource("john__doe")
+		tr, _ := SeparatorSwap("_", "", "--")(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "john_doe", "johndoe")
+	})
+
+	t.Run("Max_Bytes", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := SeparatorSwap("_", "--")(&Config{MaxBytes: 8, Source: src})
+
+		transformSequence(t, tr, src, "john_doe", "john_doe")
+	})
+
+	t.Run("All_Assignments", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := SeparatorSwap()(&Config{MaxBytes: 100, Source: src})
+
+		seen := make(map[string]bool)
+		for {
+			out, err := tr.Transform(context.Background(), MessagePacket{Message: "a.b_c"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Message == "a.b_c" {
+				break
+			}
+			if seen[out.Message] {
+				t.Fatalf("repeated %v", out.Message)
+			}
+			seen[out.Message] = true
+			src.addValue(out.Message)
+		}
+
+		// 3 separators in 2 places, without the original string.
+		if len(seen) != 8 {
+			t.Fatalf("expected 8 assignments got %v", seen)
+		}
+	})
+}
@@ -18,6 +18,10 @@ type snakeCaseTransformer struct {
 	cfg *Config
 }
 
+func (_ *snakeCaseTransformer) Describe() TransformerDescription {
+	return TransformerDescription{Name: "SnakeCase"}
+}
+
 func (t *snakeCaseTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	split := t.cfg.Tokenize(in.Message)
 	out := strings.Join(split, "_")
@@ -2,6 +2,7 @@ package sinoname
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -44,6 +45,36 @@ type symbolTransformer struct {
 	maxSymbols int
 }
 
+func (t *symbolTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "SymbolTransformer",
+		Params: []Param{
+			{"symbol", strconv.QuoteRune(t.symbol)},
+			{"max", strconv.Itoa(t.maxSymbols)},
+		},
+	}
+}
+
+// Estimate sums up the combinations of symbol positions tried for each number of symbols.
+func (t *symbolTransformer) Estimate(_ context.Context, in MessagePacket) int {
+	n := len(in.Message)
+	nr := utf8.RuneLen(t.symbol)
+
+	var total int
+	for symbolsToAdd := 1; symbolsToAdd < n+1; symbolsToAdd++ {
+		if n+symbolsToAdd*nr > t.cfg.MaxBytes {
+			break
+		}
+		if symbolsToAdd > t.maxSymbols && t.maxSymbols != 0 {
+			break
+		}
+
+		total = addSat(total, binomialSat(n+1, symbolsToAdd))
+	}
+
+	return total
+}
+
 func (t *symbolTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	var g *combin.CombinationGenerator
 	n := len(in.Message)
@@ -0,0 +1,363 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Lambels/sinoname/rng"
+)
+
+// Template builds the string from the template: {first}.{last}, {f}{last}{yy},
+// {first}_{adj}, {last}{rand:3} .
+//
+// The template is made of text and placeholders, the placeholders are:
+//   - {first}, {last}: the first name and last name from the user hints, the first and last
+//     tokens of the string (see the Tokenize config field) if there are no hints.
+//   - {f}, {l}: the first letter of {first} and {last}.
+//   - {yy}, {yyyy}: the last 2 digits and the full birth year from the user hints.
+//   - {num}: the number added via ContextWithNumber.
+//   - {adj}: each adjective provided in the config object.
+//   - {list:name}: each word of the word list with the name.
+//   - {rand:n}: each number of n digits, in a random order (1 <= n <= 9).
+//
+// Capitalized placeholders ({First}, {Adj}, ...) capitalize the value.
+//
+// The candidate expansions are tried in a defined order: the placeholders with multiple
+// values are walked like the digits of a counter, the rightmost placeholder changing
+// first. If a placeholder has no value (no hints, one token for {last}, ...) the string
+// is returned unmodified.
+//
+// Template panics if the template is invalid, use CheckTemplate to validate templates
+// provided at run time.
+var Template = func(tmpl string, lists ...*WordList) func(cfg *Config) (Transformer, bool) {
+	segments, err := parseTemplate(tmpl, lists)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(cfg *Config) (Transformer, bool) {
+		return &templateTransformer{
+			cfg:      cfg,
+			tmpl:     tmpl,
+			segments: segments,
+		}, false
+	}
+}
+
+// CheckTemplate returns the syntax errors of the template, the word lists are the lists
+// which will be passed to Template.
+func CheckTemplate(tmpl string, lists ...*WordList) error {
+	_, err := parseTemplate(tmpl, lists)
+	return err
+}
+
+var errInvalidTemplate = errors.New("sinoname: invalid template")
+
+type templateKind int
+
+const (
+	templateText templateKind = iota
+	templateFirst
+	templateLast
+	templateFirstInitial
+	templateLastInitial
+	templateShortYear
+	templateYear
+	templateNumber
+	templateAdjective
+	templateList
+	templateRandom
+)
+
+type templateSegment struct {
+	kind  templateKind
+	text  string
+	title bool
+	// digits of {rand:n}.
+	digits int
+	// list of {list:name}.
+	list *WordList
+}
+
+func parseTemplate(tmpl string, lists []*WordList) ([]templateSegment, error) {
+	var segments []templateSegment
+	for len(tmpl) > 0 {
+		start := strings.IndexByte(tmpl, '{')
+		if start < 0 {
+			segments = append(segments, templateSegment{text: tmpl})
+			break
+		}
+		if start > 0 {
+			segments = append(segments, templateSegment{text: tmpl[:start]})
+		}
+
+		end := strings.IndexByte(tmpl[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unclosed placeholder %q", errInvalidTemplate, tmpl[start:])
+		}
+		end += start
+
+		seg, err := parsePlaceholder(tmpl[start+1:end], lists)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+		tmpl = tmpl[end+1:]
+	}
+
+	return segments, nil
+}
+
+func parsePlaceholder(p string, lists []*WordList) (templateSegment, error) {
+	name, arg, hasArg := strings.Cut(p, ":")
+	first, _ := utf8.DecodeRuneInString(name)
+	seg := templateSegment{title: unicode.IsUpper(first)}
+
+	kinds := map[string]templateKind{
+		"first": templateFirst,
+		"last":  templateLast,
+		"f":     templateFirstInitial,
+		"l":     templateLastInitial,
+		"yy":    templateShortYear,
+		"yyyy":  templateYear,
+		"num":   templateNumber,
+		"adj":   templateAdjective,
+	}
+	name = strings.ToLower(name)
+	if kind, ok := kinds[name]; ok && !hasArg {
+		seg.kind = kind
+		return seg, nil
+	}
+
+	switch {
+	case name == "list" && hasArg:
+		for _, l := range lists {
+			if l.Name() == arg {
+				seg.kind = templateList
+				seg.list = l
+				return seg, nil
+			}
+		}
+		return seg, fmt.Errorf("%w: unknown word list %q", errInvalidTemplate, arg)
+
+	case name == "rand" && hasArg:
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > 9 {
+			return seg, fmt.Errorf("%w: invalid number of digits %q", errInvalidTemplate, arg)
+		}
+		seg.kind = templateRandom
+		seg.digits = n
+		return seg, nil
+	}
+
+	return seg, fmt.Errorf("%w: unknown placeholder {%s}", errInvalidTemplate, p)
+}
+
+type templateTransformer struct {
+	cfg      *Config
+	tmpl     string
+	segments []templateSegment
+}
+
+func (t *templateTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Template",
+		Params: []Param{
+			{"template", strconv.Quote(t.tmpl)},
+		},
+	}
+}
+
+// templateValues holds the values of a placeholder: n values returned by value.
+type templateValues struct {
+	n     int
+	value func(i int) string
+}
+
+// Estimate returns the number of candidate expansions.
+func (t *templateTransformer) Estimate(ctx context.Context, in MessagePacket) int {
+	values, ok := t.values(ctx, in.Message)
+	if !ok {
+		return 0
+	}
+
+	total := 1
+	for _, v := range values {
+		total = mulSat(total, v.n)
+	}
+	return total
+}
+
+func (t *templateTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	values, ok := t.values(ctx, in.Message)
+	if !ok {
+		return in, nil
+	}
+
+	counter := make([]int, len(values))
+	for {
+		select {
+		case <-ctx.Done():
+			return in, ctx.Err()
+		default:
+		}
+
+		var b strings.Builder
+		for i, v := range values {
+			b.WriteString(v.value(counter[i]))
+		}
+
+		out := b.String()
+		if out != in.Message && (t.cfg.MaxBytes <= 0 || len(out) <= t.cfg.MaxBytes) {
+			ok, err := t.cfg.Source.Valid(ctx, out)
+			if err != nil || ok {
+				in.setAndIncrement(out)
+				return in, err
+			}
+		}
+
+		// advance the counter, the rightmost placeholder first.
+		i := len(counter) - 1
+		for ; i >= 0; i-- {
+			counter[i]++
+			if counter[i] < values[i].n {
+				break
+			}
+			counter[i] = 0
+		}
+		if i < 0 {
+			return in, nil
+		}
+	}
+}
+
+// values resolves the values of each segment, false if a placeholder has no value.
+func (t *templateTransformer) values(ctx context.Context, in string) ([]templateValues, bool) {
+	hints, _ := HintsFromContext(ctx)
+	var tokens []string
+	if hints.FirstName == "" || hints.LastName == "" {
+		tokens = t.cfg.Tokenize(in)
+	}
+
+	name := func(kind templateKind) string {
+		if kind == templateFirst {
+			if hints.FirstName != "" {
+				return hints.FirstName
+			}
+			if len(tokens) > 0 {
+				return tokens[0]
+			}
+			return ""
+		}
+
+		if hints.LastName != "" {
+			return hints.LastName
+		}
+		if len(tokens) > 1 {
+			return tokens[len(tokens)-1]
+		}
+		return ""
+	}
+
+	values := make([]templateValues, len(t.segments))
+	for i, seg := range t.segments {
+		var v string
+		switch seg.kind {
+		case templateText:
+			v = seg.text
+
+		case templateFirst, templateLast:
+			v = name(seg.kind)
+
+		case templateFirstInitial, templateLastInitial:
+			kind := templateFirst
+			if seg.kind == templateLastInitial {
+				kind = templateLast
+			}
+			if n := name(kind); n != "" {
+				_, width := utf8.DecodeRuneInString(n)
+				v = n[:width]
+			}
+
+		case templateShortYear, templateYear, templateNumber:
+			hint := map[templateKind]NumberHint{
+				templateShortYear: HintShortBirthYear,
+				templateYear:      HintBirthYear,
+				templateNumber:    HintNumber,
+			}[seg.kind]
+			if n, ok := hint.number(ctx, t.cfg); ok {
+				v = n
+			}
+
+		case templateAdjective:
+			values[i] = t.words(t.cfg.Adjectives, nil, seg.title)
+
+		case templateList:
+			values[i] = t.words(nil, seg.list, seg.title)
+
+		case templateRandom:
+			values[i] = randomDigits(t.cfg, seg.digits)
+		}
+
+		if values[i].value != nil {
+			if values[i].n == 0 {
+				return nil, false
+			}
+			continue
+		}
+		if v == "" {
+			return nil, false
+		}
+
+		if seg.title && seg.kind != templateText {
+			v = capitalize(v)
+		}
+		values[i] = templateValues{1, func(int) string { return v }}
+	}
+
+	return values, true
+}
+
+// words returns the values of the words or of the list.
+func (t *templateTransformer) words(words []string, list *WordList, title bool) templateValues {
+	n := len(words)
+	word := func(i int) string { return words[i] }
+	if list != nil {
+		n = list.Len()
+		word = list.Word
+	}
+
+	return templateValues{n, func(i int) string {
+		if title {
+			return capitalize(word(i))
+		}
+		return word(i)
+	}}
+}
+
+// randomDigits returns the numbers of n digits (zero padded) in a random order without
+// repeats, see rng.AffineGen.
+func randomDigits(cfg *Config, n int) templateValues {
+	mod := 1
+	for i := 0; i < n; i++ {
+		mod *= 10
+	}
+
+	gen := rng.NewAffineGen(cfg.intn(math.MaxInt), mod)
+	return templateValues{mod, func(i int) string {
+		v := strconv.Itoa(gen.At(i))
+		return strings.Repeat("0", n-len(v)) + v
+	}}
+}
+
+// capitalize upper cases the first rune of v.
+func capitalize(v string) string {
+	r, width := utf8.DecodeRuneInString(v)
+	return string(unicode.ToUpper(r)) + v[width:]
+}
@@ -0,0 +1,65 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	t.Run("Order", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Template("{first}_{adj}")(&Config{
+			MaxBytes:   100,
+			Source:     src,
+			Tokenize:   tokenizeDefault,
+			Adjectives: []string{"quiet", "brave"},
+		})
+
+		transformSequence(t, tr, src, "john doe", "john_quiet", "john_brave", "john doe")
+	})
+
+	t.Run("Random_Digits", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Template("{first}{rand:1}")(&Config{MaxBytes: 100, Source: src, Tokenize: tokenizeDefault})
+
+		// the 10 digits are drawn without repeats.
+		seen := make(map[string]bool)
+		for i := 0; i < 10; i++ {
+			out, err := tr.Transform(context.Background(), MessagePacket{Message: "john"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := strconv.Atoi(out.Message[len("john"):]); err != nil || seen[out.Message] {
+				t.Fatalf("unexpected value %v", out.Message)
+			}
+			seen[out.Message] = true
+			src.addValue(out.Message)
+		}
+
+		tr, _ = Template("{rand:3}")(testConfig)
+		out, err := tr.Transform(context.Background(), MessagePacket{Message: "john"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Message) != 3 {
+			t.Fatalf("expected 3 zero padded digits got %v", out.Message)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, tmpl := range []string{"{first", "{unknown}", "{rand:0}", "{rand:x}", "{list:missing}"} {
+			if err := CheckTemplate(tmpl); !errors.Is(err, errInvalidTemplate) {
+				t.Fatalf("%v: expected invalid template got %v", tmpl, err)
+			}
+		}
+
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected Template to panic")
+			}
+		}()
+		Template("{first")
+	})
+}
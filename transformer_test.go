@@ -3,6 +3,7 @@ package sinoname
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 func TestTransformer(t *testing.T) {
@@ -14,6 +15,15 @@ func TestTransformer(t *testing.T) {
 
 	var testCases []testCase
 
+	hints := ContextWithHints(context.Background(), UserHints{
+		FirstName: "Patrick",
+		LastName:  "Arvatu",
+		Birthday:  time.Date(1998, time.April, 23, 0, 0, 0, 0, time.UTC),
+		Locale:    "en-US",
+		Interests: []string{"chess", "hiking"},
+		Email:     "lambels@example.com",
+	})
+
 	testCases = append(testCases,
 		testCase{t: CamelCase, in: "-.camel -case test", out: "camelCaseTest"},
 		testCase{t: PascalCase, in: "-.pascal -case test", out: "PascalCaseTest"},
@@ -33,6 +43,22 @@ func TestTransformer(t *testing.T) {
 		testCase{ContextWithNumber(context.Background(), 100), NumbersSuffix("_"), "1234Patrick", "1234Patrick_100"},
 		testCase{ContextWithNumber(context.Background(), 100), NumbersCircumfix("_"), "Patrick1234", "100_Patrick1234_100"},
 
+		testCase{hints, NumbersSuffix("", HintBirthYear), "lambels", "lambels1998"},
+		testCase{hints, NumbersPrefix("_", HintShortBirthYear), "lambels", "98_lambels"},
+		testCase{hints, NumbersSuffix("", HintBirthday), "lambels", "lambels0423"},
+		testCase{
+			ContextWithHints(context.Background(), UserHints{Birthday: time.Date(1998, time.April, 23, 0, 0, 0, 0, time.UTC), Locale: "en-GB"}),
+			NumbersSuffix("", HintBirthday),
+			"lambels",
+			"lambels2304",
+		},
+		testCase{hints, NumbersSuffix("", HintNumber), "lambels12", "lambels12"},
+		testCase{t: NumbersSuffix("", HintBirthYear), in: "lambels12", out: "lambels12"},
+		testCase{hints, Prefix(".", HintFirstName), "lambels", "Patrick.lambels"},
+		testCase{hints, Suffix(".", HintLastName), "lambels", "lambels.Arvatu"},
+		testCase{hints, Circumfix("-", HintEmail), "x", "lambels-x-lambels"},
+		testCase{ContextWithString(context.Background(), "gg"), Suffix(""), "lambels", "lambelsgg"},
+
 		testCase{t: Homoglyph(ASCIIHomoglyphLetters), in: "bee", out: "6ee"},
 
 		testCase{t: IncrementalPrefix(2, "-"), in: "FOO", out: "1-FOO"},
@@ -47,6 +73,47 @@ func TestTransformer(t *testing.T) {
 		testCase{t: AbreviationCircumfix("", true), in: "Patrick Adrian Arvatu", out: "PAA"},
 
 		testCase{t: Title, in: "123lambe3ls4", out: "123Lambe3ls4"},
+
+		testCase{t: Leetspeak(LeetOne, ASCIILeet), in: "elite", out: "3lite"},
+		testCase{t: Leetspeak(LeetAlternate, ASCIILeet), in: "elite", out: "3lit3"},
+		testCase{t: Leetspeak(LeetAll, ASCIILeet), in: "elite", out: "3l1t3"},
+		testCase{t: Leetspeak(LeetAll, ASCIILeet), in: "hacker phil", out: "h4x3r f1l"},
+		testCase{t: Leetspeak(LeetAll, ASCIILeet), in: "xyw", out: "xyw"},
+
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "Жанна Щербакова", out: "Zhanna Shcherbakova"},
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "Їжак", out: "Yizhak"},
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "Νίκος Παπαδάκης", out: "Nikos Papadakis"},
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "Renée Müller-Łukasz", out: "Renee Muller-Lukasz"},
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "Rene\u0301e", out: "Renee"},
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "lambels", out: "lambels"},
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "李 Zoë", out: "李 Zoe"},
+		testCase{t: Transliterate(CyrillicTable, GreekTable, LatinTable), in: "é\xff", out: "e\xff"},
+		testCase{t: Transliterate(RuneTable{'ö': "oe"}, LatinTable), in: "Jörg", out: "Joerg"},
+
+		testCase{t: Phonetic(), in: "Christopher", out: "Christofer"},
+		testCase{t: Phonetic(), in: "phil jones", out: "phyl jones"},
+		testCase{t: Phonetic(), in: "1234", out: "1234"},
+
+		testCase{t: Nickname(EnglishNicknames), in: "William.Smith", out: "Bill.Smith"},
+		testCase{t: Nickname(EnglishNicknames), in: "smithRobert", out: "smithBob"},
+		testCase{t: Nickname(EnglishNicknames), in: "JOHN_DOE", out: "JACK_DOE"},
+		testCase{t: Nickname(EnglishNicknames), in: "doe-john", out: "doe-jack"},
+		testCase{t: Nickname(EnglishNicknames), in: "Bill.Smith", out: "Bill.Smith"},
+		testCase{t: Nickname(EnglishNicknames.Bidirectional()), in: "Bill.Smith", out: "William.Smith"},
+
+		testCase{t: Template("{first}.{last}"), in: "john doe", out: "john.doe"},
+		testCase{t: Template("{f}{last}"), in: "JohnDoe", out: "JDoe"},
+		testCase{hints, Template("{f}{last}{yy}"), "lambels", "PArvatu98"},
+		testCase{hints, Template("{last}{yyyy}"), "lambels", "Arvatu1998"},
+		testCase{t: Template("{First}_{List:nouns}", NewWordList("nouns", "otter")), in: "john doe", out: "John_Otter"},
+		testCase{t: Template("{first}{yy}"), in: "john doe", out: "john doe"},
+		testCase{t: Template("{first}.{last}"), in: "john", out: "john"},
+		testCase{ContextWithNumber(context.Background(), 7), Template("{first}{num}"), "john doe", "john7"},
+
+		testCase{t: SeparatorSwap(), in: "john.doe_99", out: "john.doe.99"},
+		testCase{t: SeparatorSwap(), in: "John_DOE99", out: "John.DOE99"},
+		testCase{t: SeparatorSwap(), in: "johndoe", out: "johndoe"},
+		testCase{t: SeparatorSwap("-", "--"), in: "john--doe", out: "john-doe"},
 	)
 
 	// evaluate test cases.
@@ -67,3 +134,27 @@ func TestTransformer(t *testing.T) {
 		}
 	}
 }
+
+// transformSequence transforms in once per wanted value, each value is added to src
+// (taking it) before the next call. Pass in as the last wanted value to check that the
+// transformer ran out of values.
+func transformSequence(t *testing.T, tr Transformer, src *staticSrc, in string, want ...string) {
+	t.Helper()
+	transformSequenceContext(t, context.Background(), tr, src, in, want...)
+}
+
+// transformSequenceContext is transformSequence with a context.
+func transformSequenceContext(t *testing.T, ctx context.Context, tr Transformer, src *staticSrc, in string, want ...string) {
+	t.Helper()
+
+	for _, w := range want {
+		out, err := tr.Transform(ctx, MessagePacket{Message: in})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out.Message != w {
+			t.Fatalf("expected %v got %v", w, out.Message)
+		}
+		src.addValue(out.Message)
+	}
+}
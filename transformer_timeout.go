@@ -0,0 +1,124 @@
+package sinoname
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TimeoutPolicy decides what happens to a message whose transformer timed out.
+type TimeoutPolicy int
+
+const (
+	// TimeoutSkip skips the message as if the transformer returned ErrSkip.
+	TimeoutSkip TimeoutPolicy = iota
+	// TimeoutPassthrough passes the message further down the pipeline unchanged.
+	TimeoutPassthrough
+)
+
+func (p TimeoutPolicy) String() string {
+	if p == TimeoutPassthrough {
+		return "TimeoutPassthrough"
+	}
+	return "TimeoutSkip"
+}
+
+// Timeout wraps the transformer created by tFact so that each Transform call is bounded by
+// d. When the transformer doesent return in time the message is handled according to
+// policy and the pipeline keeps running.
+//
+// Timeout overrides the TransformerTimeout config field for the wrapped transformer.
+//
+//	gen.WithTransformers(
+//		sinoname.Timeout(100*time.Millisecond, sinoname.TimeoutSkip, NewDatabaseTransformer()),
+//		sinoname.CamelCase,
+//	)
+var Timeout = func(d time.Duration, policy TimeoutPolicy, tFact TransformerFactory) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		t, statefull := tFact(cfg)
+		return &deadlineTransformer{
+			cfg:    cfg,
+			t:      t,
+			d:      d,
+			policy: policy,
+		}, statefull
+	}
+}
+
+type deadlineTransformer struct {
+	cfg    *Config
+	t      Transformer
+	d      time.Duration
+	policy TimeoutPolicy
+}
+
+// Describe describes the wrapped transformer, adding the timeout parameters.
+func (t *deadlineTransformer) Describe() TransformerDescription {
+	d := describeTransformer(t.t)
+	d.Params = append(d.Params,
+		Param{"timeout", t.d.String()},
+		Param{"policy", t.policy.String()},
+	)
+	return d
+}
+
+func (t *deadlineTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	return transformWithTimeout(ctx, t.cfg, t.t, in, t.d, t.policy)
+}
+
+// transformWithTimeout runs the transformer with a timeout of d. The transformer runs in
+// its own go-routine so that transformers which dont respect the context cant hold the
+// layer, the go-routine holds the concurrency slot of the call till it returns.
+func transformWithTimeout(ctx context.Context, cfg *Config, t Transformer, in MessagePacket, d time.Duration, policy TimeoutPolicy) (MessagePacket, error) {
+	tCtx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	type result struct {
+		out MessagePacket
+		err error
+	}
+	resC := make(chan result, 1)
+	s := slotFromContext(ctx)
+	s.hold()
+	go func() {
+		defer s.done()
+		var res result
+		defer func() { resC <- res }()
+		// the panic cant be recovered by the caller from this go-routine.
+		defer recoverPanic(tCtx, &res.err)
+
+		res.out, res.err = t.Transform(tCtx, in)
+	}()
+
+	select {
+	case res := <-resC:
+		// the transformer noticed the deadline and returned the context error.
+		if !errors.Is(res.err, context.DeadlineExceeded) || tCtx.Err() == nil || ctx.Err() != nil {
+			return res.out, res.err
+		}
+
+	case <-tCtx.Done():
+		if err := ctx.Err(); err != nil {
+			return in, err
+		}
+	}
+
+	cfg.timeouts.Add(1)
+	err := observe(ctx, cfg, func() {
+		observerFor(ctx, cfg).OnTimeout(ctx, TransformEvent{
+			Layer:       layerFromContext(ctx),
+			LayerPath:   layerPathFromContext(ctx),
+			Index:       transformerFromContext(ctx),
+			Transformer: transformerNameFromContext(ctx),
+			In:          in,
+			Duration:    d,
+		})
+	})
+	if err != nil {
+		return in, err
+	}
+	if policy == TimeoutPassthrough {
+		return in, nil
+	}
+	return in, ErrSkip
+}
@@ -0,0 +1,134 @@
+package sinoname
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+type blockingTransformer struct {
+	d time.Duration
+}
+
+// Transform ignores the context on purpose.
+func (t blockingTransformer) Transform(_ context.Context, in MessagePacket) (MessagePacket, error) {
+	time.Sleep(t.d)
+	in.setAndIncrement(in.Message + "_slow")
+	return in, nil
+}
+
+func newBlockingTransformer(d time.Duration) TransformerFactory {
+	return func(cfg *Config) (Transformer, bool) {
+		return blockingTransformer{d}, false
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	newConfig := func() *Config {
+		return &Config{
+			MaxBytes:   testConfig.MaxBytes,
+			MaxVals:    testConfig.MaxVals,
+			MaxChanges: 10,
+			Source:     noopSource{true},
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		gen  func() *Generator
+		want []string
+	}{
+		{
+			name: "Skip",
+			gen: func() *Generator {
+				return New(newConfig()).WithTransformers(
+					Timeout(50*time.Millisecond, TimeoutSkip, newTimeoutTransformer("_slow", 10*time.Second)),
+					newAddTransformer("_fast"),
+				)
+			},
+			want: []string{"foo_fast"},
+		},
+		{
+			name: "Passthrough",
+			gen: func() *Generator {
+				return New(newConfig()).WithTransformers(
+					Timeout(50*time.Millisecond, TimeoutPassthrough, newTimeoutTransformer("_slow", 10*time.Second)),
+					newAddTransformer("_fast"),
+				)
+			},
+			want: []string{"foo", "foo_fast"},
+		},
+		{
+			name: "Ignored_Context",
+			gen: func() *Generator {
+				return New(newConfig()).WithTransformers(
+					Timeout(50*time.Millisecond, TimeoutSkip, newBlockingTransformer(300*time.Millisecond)),
+					newAddTransformer("_fast"),
+				)
+			},
+			want: []string{"foo_fast"},
+		},
+		{
+			name: "Config",
+			gen: func() *Generator {
+				cfg := newConfig()
+				cfg.TransformerTimeout = 50 * time.Millisecond
+				return New(cfg).WithTransformers(
+					newTimeoutTransformer("_slow", 10*time.Second),
+					newAddTransformer("_fast"),
+				)
+			},
+			want: []string{"foo_fast"},
+		},
+		{
+			name: "Override_Config",
+			gen: func() *Generator {
+				cfg := newConfig()
+				cfg.TransformerTimeout = 50 * time.Millisecond
+				return New(cfg).WithTransformers(
+					Timeout(time.Second, TimeoutSkip, newTimeoutTransformer("_slow", 100*time.Millisecond)),
+					newAddTransformer("_fast"),
+				)
+			},
+			want: []string{"foo_fast", "foo_slow"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gen := tc.gen()
+
+			start := time.Now()
+			vals, err := gen.Generate(context.Background(), "foo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if time.Since(start) > time.Second {
+				t.Fatal("expected the timeout to release the layer")
+			}
+
+			if len(vals) != len(tc.want) {
+				t.Fatalf("expected %v but got %v", tc.want, vals)
+			}
+			for _, w := range tc.want {
+				var found bool
+				for _, v := range vals {
+					found = found || v == w
+				}
+				if !found {
+					t.Fatalf("expected %v but got %v", tc.want, vals)
+				}
+			}
+
+			var wantTimeouts uint64 = 1
+			if tc.name == "Override_Config" {
+				wantTimeouts = 0
+			}
+			if n := gen.Timeouts(); n != wantTimeouts {
+				t.Fatalf("expected %v timeouts but got %v", wantTimeouts, n)
+			}
+		})
+	}
+}
@@ -17,6 +17,10 @@ type titleTransformer struct {
 	cfg *Config
 }
 
+func (_ *titleTransformer) Describe() TransformerDescription {
+	return TransformerDescription{Name: "Title"}
+}
+
 func (t *titleTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
 	i := strings.IndexFunc(in.Message, unicode.IsLetter)
 	if i == -1 {
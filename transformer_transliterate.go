@@ -0,0 +1,201 @@
+package sinoname
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TransliterationTable transliterates the runes of a script to ascii.
+type TransliterationTable interface {
+	// Transliterate returns the ascii transliteration of r and true if r is part of the
+	// table.
+	Transliterate(r rune) (string, bool)
+}
+
+// RuneTable is a TransliterationTable mapping each rune to its transliteration.
+type RuneTable map[rune]string
+
+func (t RuneTable) Transliterate(r rune) (string, bool) {
+	v, ok := t[r]
+	return v, ok
+}
+
+// withUpper adds the upper case runes of the table, mapped to the capitalized
+// transliteration of the lower case runes.
+func withUpper(t RuneTable) RuneTable {
+	out := make(RuneTable, 2*len(t))
+	for r, v := range t {
+		out[r] = v
+
+		upper := unicode.ToUpper(r)
+		if upper == r {
+			continue
+		}
+		if v == "" {
+			out[upper] = ""
+			continue
+		}
+		out[upper] = strings.ToUpper(v[:1]) + v[1:]
+	}
+
+	return out
+}
+
+// CyrillicTable transliterates the russian, ukrainian and belarusian alphabets.
+var CyrillicTable RuneTable = withUpper(RuneTable{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+
+	'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g", 'ў': "u",
+})
+
+// GreekTable transliterates the greek alphabet, including the accented vowels.
+var GreekTable RuneTable = withUpper(RuneTable{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+
+	'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o", 'ϊ': "i",
+	'ϋ': "y", 'ΐ': "i", 'ΰ': "y",
+})
+
+// LatinTable transliterates the latin letters with diacritics (Latin-1 Supplement and
+// Latin Extended-A) and drops the combining diacritical marks.
+var LatinTable TransliterationTable = latinTable{withUpper(RuneTable{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a",
+	'ą': "a", 'æ': "ae",
+	'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e",
+	'ě': "e",
+	'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i",
+	'ĳ': "ij",
+	'ĵ': "j",
+	'ķ': "k",
+	'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+	'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o",
+	'ő': "o", 'œ': "oe",
+	'ŕ': "r", 'ŗ': "r", 'ř': "r",
+	'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s",
+	'ţ': "t", 'ť': "t", 'ŧ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u",
+	'ű': "u", 'ų': "u",
+	'ŵ': "w",
+	'ý': "y", 'ÿ': "y", 'ŷ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}), RuneTable{'ß': "ss", 'ı': "i", 'ĸ': "k", 'ŉ': "n", 'ſ': "s"}}
+
+type latinTable struct {
+	letters RuneTable
+	// lower case letters without an upper case form.
+	extra RuneTable
+}
+
+func (t latinTable) Transliterate(r rune) (string, bool) {
+	if unicode.Is(unicode.Mn, r) {
+		return "", true
+	}
+	if v, ok := t.letters[r]; ok {
+		return v, true
+	}
+
+	return t.extra.Transliterate(r)
+}
+
+// Transliterate transliterates the string to ascii using the provided tables, the first
+// table which knows a rune is used. Runes unknown to all the tables are kept.
+//
+// Cyrillic, greek and latin letters with diacritics are supported by CyrillicTable,
+// GreekTable and LatinTable, other scripts can be supported by implementing
+// TransliterationTable.
+//
+// Жанна Müller -> Zhanna Muller
+var Transliterate = func(tables ...TransliterationTable) func(cfg *Config) (Transformer, bool) {
+	return func(cfg *Config) (Transformer, bool) {
+		return &transliterateTransformer{
+			cfg:    cfg,
+			tables: tables,
+		}, false
+	}
+}
+
+type transliterateTransformer struct {
+	cfg    *Config
+	tables []TransliterationTable
+}
+
+func (t *transliterateTransformer) Describe() TransformerDescription {
+	return TransformerDescription{
+		Name: "Transliterate",
+		Params: []Param{
+			{"tables", strconv.Itoa(len(t.tables))},
+		},
+	}
+}
+
+func (t *transliterateTransformer) Transform(ctx context.Context, in MessagePacket) (MessagePacket, error) {
+	var b strings.Builder
+	var modified bool
+	for i := 0; i < len(in.Message); {
+		c, width := utf8.DecodeRuneInString(in.Message[i:])
+		raw := in.Message[i : i+width]
+		i += width
+
+		// FASTPATH: ascii is never transliterated.
+		var v string
+		var ok bool
+		if c >= utf8.RuneSelf {
+			v, ok = t.transliterate(c)
+		}
+		if !ok {
+			if modified {
+				b.WriteString(raw)
+			}
+			continue
+		}
+
+		// CoW.
+		if !modified {
+			b.Grow(len(in.Message))
+			b.WriteString(in.Message[:i-width])
+			modified = true
+		}
+		b.WriteString(v)
+	}
+
+	if !modified {
+		return in, nil
+	}
+
+	out := b.String()
+	if out == "" || (t.cfg.MaxBytes > 0 && len(out) > t.cfg.MaxBytes) {
+		return in, nil
+	}
+
+	ok, err := t.cfg.Source.Valid(ctx, out)
+	if ok {
+		in.setAndIncrement(out)
+	}
+
+	return in, err
+}
+
+func (t *transliterateTransformer) transliterate(r rune) (string, bool) {
+	for _, table := range t.tables {
+		if v, ok := table.Transliterate(r); ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,20 @@
+package sinoname
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	t.Run("Max_Bytes", func(t *testing.T) {
+		src := newStaticSource()
+		tr, _ := Transliterate(CyrillicTable)(&Config{MaxBytes: 4, Source: src})
+
+		// Shchuka is longer than 4 bytes.
+		transformSequence(t, tr, src, "Щука", "Щука")
+	})
+
+	t.Run("Taken", func(t *testing.T) {
+		src := newStaticSource("Zoe")
+		tr, _ := Transliterate(LatinTable)(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "Zoë", "Zoë")
+	})
+}
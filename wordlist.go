@@ -0,0 +1,253 @@
+package sinoname
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// WordList is a named list of words used by the affix transformers (PrefixFrom,
+// SuffixFrom, CircumfixFrom). Each word can carry tags (for example the category or the
+// tone of the word).
+//
+// A WordList carries a random permutation of its words computed once when the list is
+// built, it is immutable and safe for concurrent use.
+type WordList struct {
+	name  string
+	words []string
+	tags  [][]string
+	// perm is the random permutation of the indexes of words.
+	perm []int
+}
+
+// NewWordList creates a word list from the words, duplicate and empty words are dropped.
+func NewWordList(name string, words ...string) *WordList {
+	l := &WordList{name: name}
+	seen := make(map[string]int, len(words))
+	for _, w := range words {
+		l.add(seen, w, nil)
+	}
+	l.shuffle()
+
+	return l
+}
+
+// LoadWordList reads a word list from r. Each line holds a word optionally followed by
+// comma separated tags, lines starting with # are ignored:
+//
+//	otter,animal,cute
+//	falcon,animal
+//	quiet
+func LoadWordList(name string, r io.Reader) (*WordList, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	l := &WordList{name: name}
+	seen := make(map[string]int)
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var tags []string
+		for _, tag := range record[1:] {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		l.add(seen, record[0], tags)
+	}
+	l.shuffle()
+
+	return l, nil
+}
+
+// LoadWordListFile reads a word list from the file (see LoadWordList), the list is named
+// after the file without its extension.
+func LoadWordListFile(path string) (*WordList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	base := filepath.Base(path)
+	return LoadWordList(strings.TrimSuffix(base, filepath.Ext(base)), f)
+}
+
+// MergeWordLists merges the lists in a new list, the tags of the words found in multiple
+// lists are merged.
+func MergeWordLists(name string, lists ...*WordList) *WordList {
+	l := &WordList{name: name}
+	seen := make(map[string]int)
+	for _, list := range lists {
+		for i, w := range list.words {
+			l.add(seen, w, list.tags[i])
+		}
+	}
+	l.shuffle()
+
+	return l
+}
+
+// add adds the word with its tags to the list, merging the tags of duplicate words.
+func (l *WordList) add(seen map[string]int, w string, tags []string) {
+	w = strings.TrimSpace(w)
+	if w == "" {
+		return
+	}
+
+	i, ok := seen[w]
+	if !ok {
+		seen[w] = len(l.words)
+		l.words = append(l.words, w)
+		l.tags = append(l.tags, append([]string(nil), tags...))
+		return
+	}
+
+	for _, tag := range tags {
+		if !containsString(l.tags[i], tag) {
+			l.tags[i] = append(l.tags[i], tag)
+		}
+	}
+}
+
+func (l *WordList) shuffle() {
+	l.perm = rand.Perm(len(l.words))
+}
+
+// Name returns the name of the list.
+func (l *WordList) Name() string {
+	return l.name
+}
+
+// Len returns the number of words in the list.
+func (l *WordList) Len() int {
+	return len(l.words)
+}
+
+// Words returns a copy of the words in the order they were added.
+func (l *WordList) Words() []string {
+	return append([]string(nil), l.words...)
+}
+
+// Tags returns the tags of the word, nil if the word isnt part of the list.
+func (l *WordList) Tags(word string) []string {
+	for i, w := range l.words {
+		if w == word {
+			return append([]string(nil), l.tags[i]...)
+		}
+	}
+
+	return nil
+}
+
+// Word returns the i-th word of the random permutation of the list.
+func (l *WordList) Word(i int) string {
+	return l.words[l.perm[i]]
+}
+
+// Filter returns a new list, with the same name, holding the words accepted by all the
+// filters.
+func (l *WordList) Filter(filters ...WordFilter) *WordList {
+	out := &WordList{name: l.name}
+	seen := make(map[string]int)
+L:
+	for i, w := range l.words {
+		for _, f := range filters {
+			if !f(w, l.tags[i]) {
+				continue L
+			}
+		}
+		out.add(seen, w, l.tags[i])
+	}
+	out.shuffle()
+
+	return out
+}
+
+// WordFilter reports whether the word, with its tags, should be kept in the list.
+type WordFilter func(word string, tags []string) bool
+
+// WithLength keeps the words with a length (in runes) between min and max, max <= 0
+// means no upper limit.
+func WithLength(min, max int) WordFilter {
+	return func(word string, _ []string) bool {
+		n := utf8.RuneCountInString(word)
+		return n >= min && (max <= 0 || n <= max)
+	}
+}
+
+// WithCharset keeps the words made only of runes from the charset.
+func WithCharset(charset string) WordFilter {
+	return func(word string, _ []string) bool {
+		for _, r := range word {
+			if !strings.ContainsRune(charset, r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// WithTag keeps the words tagged with any of the tags.
+func WithTag(tags ...string) WordFilter {
+	return func(_ string, wordTags []string) bool {
+		for _, tag := range tags {
+			if containsString(wordTags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// WithoutTag drops the words tagged with any of the tags.
+func WithoutTag(tags ...string) WordFilter {
+	keep := WithTag(tags...)
+	return func(word string, wordTags []string) bool {
+		return !keep(word, wordTags)
+	}
+}
+
+// WithoutWords drops the words containing any of the denied words (case insensitive), use
+// it to filter out profanity:
+//
+//	list.Filter(sinoname.WithoutWords(myProfanityList...))
+func WithoutWords(deny ...string) WordFilter {
+	lower := make([]string, len(deny))
+	for i, d := range deny {
+		lower[i] = strings.ToLower(d)
+	}
+
+	return func(word string, _ []string) bool {
+		word = strings.ToLower(word)
+		for _, d := range lower {
+			if d != "" && strings.Contains(word, d) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func containsString(slc []string, v string) bool {
+	for _, s := range slc {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,130 @@
+package sinoname
+
+import (
+	"context"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestWordList(t *testing.T) {
+	const data = `# animals
+otter, animal, cute
+falcon,animal
+quiet
+Crap,profanity
+otter,water
+`
+
+	t.Run("Load", func(t *testing.T) {
+		l, err := LoadWordList("words", strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if l.Name() != "words" || l.Len() != 4 {
+			t.Fatalf("expected 4 words got %v", l.Words())
+		}
+		if got := strings.Join(l.Tags("otter"), ","); got != "animal,cute,water" {
+			t.Fatalf("expected merged tags got %v", got)
+		}
+
+		// the permutation holds each word once.
+		var perm []string
+		for i := 0; i < l.Len(); i++ {
+			perm = append(perm, l.Word(i))
+		}
+		words := l.Words()
+		sort.Strings(perm)
+		sort.Strings(words)
+		if strings.Join(perm, ",") != strings.Join(words, ",") {
+			t.Fatalf("expected permutation of %v got %v", words, perm)
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nouns.txt")
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		l, err := LoadWordListFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l.Name() != "nouns" || l.Len() != 4 {
+			t.Fatalf("expected 4 words in nouns got %v %v", l.Name(), l.Words())
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		l, _ := LoadWordList("words", strings.NewReader(data))
+
+		for _, tc := range []struct {
+			name    string
+			filters []WordFilter
+			want    string
+		}{
+			{"Length", []WordFilter{WithLength(5, 5)}, "otter,quiet"},
+			{"Charset", []WordFilter{WithCharset("abcdefghijklmnopqrstuvwxyz")}, "otter,falcon,quiet"},
+			{"Tag", []WordFilter{WithTag("animal")}, "otter,falcon"},
+			{"Without_Tag", []WordFilter{WithoutTag("animal", "profanity")}, "quiet"},
+			{"Without_Words", []WordFilter{WithoutWords("CRAP", "alc")}, "otter,quiet"},
+			{"Combined", []WordFilter{WithTag("animal"), WithLength(6, 0)}, "falcon"},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				if got := strings.Join(l.Filter(tc.filters...).Words(), ","); got != tc.want {
+					t.Fatalf("expected %v got %v", tc.want, got)
+				}
+			})
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		a := NewWordList("a", "otter", "falcon", "")
+		b := NewWordList("b", "falcon", "quiet")
+
+		m := MergeWordLists("ab", a, b)
+		if m.Name() != "ab" || strings.Join(m.Words(), ",") != "otter,falcon,quiet" {
+			t.Fatalf("expected otter,falcon,quiet got %v", m.Words())
+		}
+	})
+
+	t.Run("Affix", func(t *testing.T) {
+		list := NewWordList("nouns", "otter", "falcon")
+		src := newStaticSource("lambels_otter")
+		tr, _ := SuffixFrom(list, "_")(&Config{MaxBytes: 100, Source: src})
+
+		transformSequence(t, tr, src, "lambels", "lambels_falcon", "lambels")
+
+		want := `Suffix(sep="_", list="nouns")`
+		if got := describeTransformer(tr).String(); got != want {
+			t.Fatalf("expected %v got %v", want, got)
+		}
+	})
+
+	t.Run("Affix_RandSrc", func(t *testing.T) {
+		list := NewWordList("nouns", AnimalNouns...)
+		draw := func() []string {
+			cfg := &Config{MaxBytes: 100, Source: noopSource{true}, RandSrc: rand.New(rand.NewSource(1))}
+			tr, _ := SuffixFrom(list, "_")(cfg)
+
+			var out []string
+			for i := 0; i < 5; i++ {
+				v, err := tr.Transform(context.Background(), MessagePacket{Message: "lambels"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				out = append(out, v.Message)
+			}
+			return out
+		}
+
+		if a, b := draw(), draw(); strings.Join(a, ",") != strings.Join(b, ",") {
+			t.Fatalf("expected the same values from the same seed got %v and %v", a, b)
+		}
+	})
+}